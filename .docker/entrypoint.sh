@@ -26,9 +26,6 @@ psql -U postgres -c "CREATE USER ${POSTGRES_USER} WITH PASSWORD '${POSTGRES_PASS
 psql -U postgres -c "CREATE DATABASE ${POSTGRES_DB};"
 psql -U postgres -c "GRANT ALL PRIVILEGES ON DATABASE ${POSTGRES_DB} TO ${POSTGRES_USER};"
 
-echo "executing migrations..."
-/usr/local/bin/migrate.linux-amd64 -path /migrations-server -database "${DATABASE_URL}" up
-
 echo "Iniciando o Maestro Server..."
 
 /maestro-server &
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/JMCDynamics/maestro-server/internal/adapters"
+	"github.com/JMCDynamics/maestro-server/internal/config"
+)
+
+const healthcheckTimeout = 5 * time.Second
+
+// healthcheckCommand asks the running server's /readyz whether it is ready,
+// over the same scheme and address the server listens on, so container
+// health checks follow TLS and LISTEN_ON_WIREGUARD.
+func healthcheckCommand(env config.Env) error {
+	address, err := listenAddress(env, adapters.NewWireguardAdapter(env.WireguardEndpoint))
+	if err != nil {
+		return fmt.Errorf("unable to resolve listen address: %v", err)
+	}
+
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "localhost"
+	}
+
+	scheme := "http"
+	client := &http.Client{Timeout: healthcheckTimeout}
+	if env.TLSEnabled() {
+		scheme = "https"
+		// the certificate need not name the address probed from inside the
+		// container; this only checks that the server answers
+		client.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		}
+	}
+
+	resp, err := client.Get(scheme + "://" + net.JoinHostPort(host, port) + "/readyz")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("server is not ready: %s", resp.Status)
+	}
+
+	return nil
+}
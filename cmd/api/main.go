@@ -1,6 +1,17 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
 	"github.com/ardanlabs/conf/v3"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
@@ -9,40 +20,114 @@ import (
 	"github.com/JMCDynamics/maestro-server/internal/adapters"
 	"github.com/JMCDynamics/maestro-server/internal/config"
 	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
 	"github.com/JMCDynamics/maestro-server/internal/server"
 	"github.com/JMCDynamics/maestro-server/internal/services"
 	usecases "github.com/JMCDynamics/maestro-server/internal/use-cases"
+	"github.com/JMCDynamics/maestro-server/internal/utils"
 )
 
 func main() {
 	var env config.Env
-	if _, err := conf.Parse("", &env); err != nil {
-		panic(err)
+	if err := config.LoadSecretFiles(&env); err != nil {
+		log.Fatal().Err(err).Msg("unable to load secret files")
+	}
+
+	help, err := conf.Parse("", &env)
+	if errors.Is(err, conf.ErrHelpWanted) {
+		fmt.Println(help)
+		return
+	}
+	if err != nil {
+		log.Fatal().Err(err).Msg("unable to parse configuration")
+	}
+
+	if err := env.Validate(); err != nil {
+		log.Fatal().Err(err).Msg("invalid configuration")
 	}
 
-	databaseConfig := config.NewDatabaseConfig()
+	if env.Args.Num(0) == "migrate" {
+		if err := migrateCommand(env, env.Args[1:]); err != nil {
+			log.Fatal().Err(err).Msg("migration failed")
+		}
+		return
+	}
+	if env.Args.Num(0) == "healthcheck" {
+		if err := healthcheckCommand(env); err != nil {
+			log.Fatal().Err(err).Msg("health check failed")
+		}
+		return
+	}
+	if len(env.Args) > 0 {
+		log.Fatal().Str("command", env.Args.Num(0)).Msg("unknown command")
+	}
+
+	jwtKeySet, err := env.JWTKeySet()
+	if err != nil {
+		log.Fatal().Err(err).Msg("unable to load jwt keys")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
 		v.RegisterValidation("operatingsystem", dtos.ValidateOperatingSystem)
+		v.RegisterValidation("cron", dtos.ValidateCronExpression)
+		v.RegisterValidation("role", dtos.ValidateRole)
+		v.RegisterValidation("permission", dtos.ValidatePermission)
+		v.RegisterTagNameFunc(dtos.FieldName)
 	}
 
-	databaseGateway, err := adapters.NewDatabaseGateway(databaseConfig.UrlConnection())
+	shutdownTracing, err := adapters.NewTracerProvider(
+		ctx,
+		dtos.TracingExporter(env.Tracing.Exporter),
+		env.Tracing.Endpoint,
+		env.Tracing.ServiceName,
+		env.Tracing.SampleRatio,
+	)
 	if err != nil {
-		panic(err)
+		log.Fatal().Err(err).Msg("unable to set up tracing")
 	}
 
-	if err := databaseGateway.RunMigrations(); err != nil {
+	databaseGateway, err := adapters.NewDatabaseGateway(env.Database.UrlConnection())
+	if err != nil {
 		panic(err)
 	}
 
+	if env.Database.AutoMigrate {
+		if err := databaseGateway.RunMigrations(); err != nil {
+			panic(err)
+		}
+	}
+
 	vpnGateway := adapters.NewWireguardAdapter(env.WireguardEndpoint)
 
 	nodeStatusService := services.NewNodeStatusService()
+	jobRegistryService := services.NewJobRegistryService()
+	shellSessionService := services.NewShellSessionService()
 
-	cacheGateway := adapters.NewRedisCacheAdapter(databaseConfig.RedisUrlConnection(), databaseConfig.RedisPassword, 0)
+	redisTLSConfig, err := env.Redis.TLSConfig()
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid redis tls configuration")
+	}
+
+	cacheGateway := adapters.NewRedisCacheAdapter(
+		env.Redis.Addr(),
+		env.Redis.Username,
+		env.Redis.Password,
+		env.Redis.DB,
+		env.Redis.PoolSize,
+		redisTLSConfig,
+	)
 
 	go func() {
 		for key := range cacheGateway.ListenExpiredKeys() {
+			// node heartbeats are stored under the bare node id, everything
+			// else in redis is namespaced with a colon
+			if strings.Contains(key, ":") {
+				continue
+			}
+
 			log.Info().Str("node-id", key).Msg("node expired")
 
 			nodeStatusService.SetStatus(dtos.NodeStatus{
@@ -52,26 +137,305 @@ func main() {
 		}
 	}()
 
-	findNodesUseCase := usecases.NewLoggerUseCase(
+	findNodesUseCase := usecases.NewLoggerUseCase(usecases.NewTracingUseCase(
 		usecases.NewFindNodesUseCase(databaseGateway, cacheGateway),
-	)
-	findNodeUseCase := usecases.NewLoggerUseCase(
+	))
+	findNodeUseCase := usecases.NewLoggerUseCase(usecases.NewTracingUseCase(
 		usecases.NewFindNodeUseCase(databaseGateway, cacheGateway),
-	)
-	createNodeUseCase := usecases.NewLoggerUseCase(
+	))
+	createNodeUseCase := usecases.NewLoggerUseCase(usecases.NewTracingUseCase(
 		usecases.NewCreateNode(databaseGateway, vpnGateway),
+	))
+	deleteNodeUseCase := usecases.NewLoggerUseCase(usecases.NewTracingUseCase(
+		usecases.NewDeleteNodeUseCase(databaseGateway, cacheGateway, vpnGateway, findNodeUseCase),
+	))
+	canAccessNodeUseCase := usecases.NewTracingUseCase(usecases.NewCanAccessNodeUseCase(databaseGateway))
+	createNodeGroupUseCase := usecases.NewLoggerUseCase(usecases.NewTracingUseCase(
+		usecases.NewCreateNodeGroupUseCase(databaseGateway),
+	))
+	findNodeGroupsUseCase := usecases.NewLoggerUseCase(usecases.NewTracingUseCase(
+		usecases.NewFindNodeGroupsUseCase(databaseGateway),
+	))
+	findNodeGroupUseCase := usecases.NewTracingUseCase(usecases.NewFindNodeGroupUseCase(databaseGateway))
+	updateNodeGroupUseCase := usecases.NewLoggerUseCase(usecases.NewTracingUseCase(
+		usecases.NewUpdateNodeGroupUseCase(databaseGateway, findNodeGroupUseCase),
+	))
+	deleteNodeGroupUseCase := usecases.NewLoggerUseCase(usecases.NewTracingUseCase(
+		usecases.NewDeleteNodeGroupUseCase(databaseGateway, findNodeGroupUseCase),
+	))
+	createSessionUseCase := usecases.NewTracingUseCase(
+		usecases.NewCreateSessionUseCase(
+			databaseGateway,
+			jwtKeySet,
+			env.AccessTokenTTL,
+			env.RefreshTokenTTL,
+		),
+	)
+	recordAuditEventUseCase := usecases.NewTracingUseCase(
+		usecases.NewRecordAuditEventUseCase(databaseGateway),
+	)
+	findAuditLogUseCase := usecases.NewTracingUseCase(usecases.NewFindAuditLogUseCase(databaseGateway))
+	checkReadinessUseCase := usecases.NewTracingUseCase(
+		usecases.NewCheckReadinessUseCase(databaseGateway, cacheGateway, vpnGateway),
+	)
+	authenticateUserUseCase := usecases.NewTracingUseCase(
+		usecases.NewThrottleLoginUseCase(
+			cacheGateway,
+			usecases.NewAuthenticateUserUseCase(
+				databaseGateway,
+				cacheGateway,
+				createSessionUseCase,
+				env.TotpIssuer,
+			),
+			recordAuditEventUseCase,
+			env.LoginThrottlePolicy(),
+		),
+	)
+	verifyTwoFactorUseCase := usecases.NewThrottleIpUseCase(
+		cacheGateway,
+		usecases.NewTracingUseCase(
+			usecases.NewVerifyTwoFactorUseCase(
+				databaseGateway,
+				cacheGateway,
+				createSessionUseCase,
+				env.LoginThrottlePolicy(),
+			),
+		),
+		env.LoginThrottlePolicy(),
+		func(data dtos.VerifyTwoFactorDTO) string { return data.IpAddress },
+		usecases.ErrInvalidTwoFactorCode,
+		usecases.ErrInvalidChallenge,
+	)
+	enrollTotpUseCase := usecases.NewTracingUseCase(
+		usecases.NewEnrollTotpUseCase(databaseGateway, env.TotpIssuer),
+	)
+	confirmTotpUseCase := usecases.NewTracingUseCase(
+		usecases.NewConfirmTotpUseCase(databaseGateway, cacheGateway),
+	)
+	disableTotpUseCase := usecases.NewTracingUseCase(
+		usecases.NewDisableTotpUseCase(databaseGateway, cacheGateway),
+	)
+	regenerateRecoveryCodesUseCase := usecases.NewTracingUseCase(
+		usecases.NewRegenerateRecoveryCodesUseCase(databaseGateway, cacheGateway),
+	)
+	findAuthPolicyUseCase := usecases.NewLoggerUseCase(usecases.NewTracingUseCase(
+		usecases.NewFindAuthPolicyUseCase(databaseGateway),
+	))
+	updateAuthPolicyUseCase := usecases.NewLoggerUseCase(usecases.NewTracingUseCase(
+		usecases.NewUpdateAuthPolicyUseCase(databaseGateway),
+	))
+	refreshSessionUseCase := usecases.NewThrottleIpUseCase(
+		cacheGateway,
+		usecases.NewTracingUseCase(
+			usecases.NewRefreshSessionUseCase(
+				databaseGateway,
+				cacheGateway,
+				jwtKeySet,
+				env.AccessTokenTTL,
+				env.RefreshTokenTTL,
+			),
+		),
+		env.LoginThrottlePolicy(),
+		func(data dtos.RefreshSessionDTO) string { return data.IpAddress },
+		usecases.ErrInvalidRefreshToken,
+		usecases.ErrRefreshTokenReused,
+	)
+	findSessionsUseCase := usecases.NewTracingUseCase(usecases.NewFindSessionsUseCase(databaseGateway))
+	revokeSessionUseCase := usecases.NewLoggerUseCase(usecases.NewTracingUseCase(
+		usecases.NewRevokeSessionUseCase(databaseGateway, cacheGateway, env.AccessTokenTTL),
+	))
+	createApiKeyUseCase := usecases.NewTracingUseCase(usecases.NewCreateApiKeyUseCase(databaseGateway))
+	findApiKeysUseCase := usecases.NewLoggerUseCase(usecases.NewTracingUseCase(
+		usecases.NewFindApiKeysUseCase(databaseGateway),
+	))
+	deleteApiKeyUseCase := usecases.NewLoggerUseCase(usecases.NewTracingUseCase(
+		usecases.NewDeleteApiKeyUseCase(databaseGateway),
+	))
+	authenticateApiKeyUseCase := usecases.NewTracingUseCase(
+		usecases.NewAuthenticateApiKeyUseCase(databaseGateway),
+	)
+	var (
+		startOidcLoginUseCase    interfaces.IUseCase[any, dtos.OidcLogin]
+		completeOidcLoginUseCase interfaces.IUseCase[dtos.OidcCallbackDTO, dtos.TokenPair]
+	)
+	if env.OidcEnabled() {
+		roleMapping, defaultRole, err := oidcRoles(env)
+		if err != nil {
+			panic(err)
+		}
+
+		identityProviderGateway := adapters.NewOidcAdapter(
+			env.OidcIssuerURL,
+			env.OidcClientId,
+			env.OidcClientSecret,
+			env.OidcRedirectURL,
+			env.OidcScopes,
+			env.OidcUsernameClaim,
+			env.OidcGroupsClaim,
+		)
+		startOidcLoginUseCase = usecases.NewTracingUseCase(
+			usecases.NewStartOidcLoginUseCase(cacheGateway, identityProviderGateway),
+		)
+		completeOidcLoginUseCase = usecases.NewTracingUseCase(
+			usecases.NewCompleteOidcLoginUseCase(
+				databaseGateway,
+				cacheGateway,
+				identityProviderGateway,
+				createSessionUseCase,
+				roleMapping,
+				defaultRole,
+			),
+		)
+	}
+	logoutUseCase := usecases.NewTracingUseCase(
+		usecases.NewLogoutUseCase(databaseGateway, cacheGateway, env.AccessTokenTTL),
+	)
+	isTokenRevokedUseCase := usecases.NewTracingUseCase(usecases.NewIsTokenRevokedUseCase(cacheGateway))
+	setUpNodeUseCase := usecases.NewTracingUseCase(usecases.NewSetNodeUpUseCase(cacheGateway))
+	updateNodeUseCase := usecases.NewTracingUseCase(
+		usecases.NewUpdateNodeUseCase(databaseGateway, cacheGateway),
+	)
+	broadcastUseCase := usecases.NewLoggerUseCase(usecases.NewTracingUseCase(
+		usecases.NewBroadcastToNodesUseCase(findNodesUseCase),
+	))
+
+	// exec is not logged: its input carries environment values, which often
+	// hold secrets
+	execCommandUseCase := usecases.NewTracingUseCase(
+		usecases.NewExecCommandUseCase(databaseGateway, findNodeUseCase, jobRegistryService),
+	)
+	findJobsUseCase := usecases.NewLoggerUseCase(usecases.NewTracingUseCase(
+		usecases.NewFindJobsUseCase(databaseGateway),
+	))
+	findJobUseCase := usecases.NewTracingUseCase(usecases.NewFindJobUseCase(databaseGateway, canAccessNodeUseCase))
+	cancelJobUseCase := usecases.NewLoggerUseCase(usecases.NewTracingUseCase(
+		usecases.NewCancelJobUseCase(findJobUseCase, jobRegistryService),
+	))
+
+	openShellSessionUseCase := usecases.NewTracingUseCase(
+		usecases.NewOpenShellSessionUseCase(
+			databaseGateway,
+			shellSessionService,
+			env.ShellIdleTimeout,
+			env.ShellRecordingsPath,
+		),
+	)
+	findShellSessionsUseCase := usecases.NewLoggerUseCase(usecases.NewTracingUseCase(
+		usecases.NewFindShellSessionsUseCase(databaseGateway),
+	))
+	findShellSessionUseCase := usecases.NewTracingUseCase(
+		usecases.NewFindShellSessionUseCase(databaseGateway),
+	)
+	closeShellSessionUseCase := usecases.NewLoggerUseCase(usecases.NewTracingUseCase(
+		usecases.NewCloseShellSessionUseCase(shellSessionService),
+	))
+
+	listNodeFilesUseCase := usecases.NewLoggerUseCase(usecases.NewTracingUseCase(
+		usecases.NewListNodeFilesUseCase(findNodeUseCase),
+	))
+	statNodeFileUseCase := usecases.NewTracingUseCase(usecases.NewStatNodeFileUseCase(findNodeUseCase))
+	uploadNodeFileUseCase := usecases.NewLoggerUseCase(usecases.NewTracingUseCase(
+		usecases.NewUploadNodeFileUseCase(cacheGateway, findNodeUseCase),
+	))
+	downloadNodeFileUseCase := usecases.NewLoggerUseCase(usecases.NewTracingUseCase(
+		usecases.NewDownloadNodeFileUseCase(findNodeUseCase),
+	))
+
+	// nor are the use cases that take or return a schedule's action, for the
+	// same reason
+	createScheduleUseCase := usecases.NewTracingUseCase(
+		usecases.NewCreateScheduleUseCase(databaseGateway),
+	)
+	findSchedulesUseCase := usecases.NewTracingUseCase(
+		usecases.NewFindSchedulesUseCase(databaseGateway),
+	)
+	findScheduleUseCase := usecases.NewTracingUseCase(usecases.NewFindScheduleUseCase(databaseGateway))
+	updateScheduleUseCase := usecases.NewTracingUseCase(
+		usecases.NewUpdateScheduleUseCase(databaseGateway, findScheduleUseCase),
+	)
+	deleteScheduleUseCase := usecases.NewLoggerUseCase(usecases.NewTracingUseCase(
+		usecases.NewDeleteScheduleUseCase(databaseGateway, findScheduleUseCase),
+	))
+	findScheduleRunsUseCase := usecases.NewTracingUseCase(
+		usecases.NewFindScheduleRunsUseCase(databaseGateway),
+	)
+	findUserUseCase := usecases.NewTracingUseCase(usecases.NewFindUserUseCase(databaseGateway))
+	runDueSchedulesUseCase := usecases.NewTracingUseCase(
+		usecases.NewRunDueSchedulesUseCase(
+			databaseGateway,
+			cacheGateway,
+			findUserUseCase,
+			findNodesUseCase,
+			broadcastUseCase,
+			execCommandUseCase,
+			jobRegistryService,
+		),
+	)
+
+	go func() {
+		ticker := time.NewTicker(env.SchedulerInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				fired, err := runDueSchedulesUseCase.Execute(ctx, now)
+				if err != nil {
+					log.Error().Err(err).Msg("unable to run due schedules")
+					continue
+				}
+
+				if fired > 0 {
+					log.Info().Int("fired", fired).Msg("schedules fired")
+				}
+			}
+		}
+	}()
+
+	findUsersUseCase := usecases.NewLoggerUseCase(usecases.NewTracingUseCase(
+		usecases.NewFindUsersUseCase(databaseGateway),
+	))
+	createUserUseCase := usecases.NewTracingUseCase(usecases.NewCreateUserUseCase(databaseGateway))
+	revokeUserSessionsUseCase := usecases.NewLoggerUseCase(usecases.NewTracingUseCase(
+		usecases.NewRevokeUserSessionsUseCase(databaseGateway, cacheGateway, findUserUseCase, env.AccessTokenTTL),
+	))
+	updateUserUseCase := usecases.NewLoggerUseCase(usecases.NewTracingUseCase(
+		usecases.NewUpdateUserUseCase(
+			databaseGateway,
+			cacheGateway,
+			findUserUseCase,
+			revokeUserSessionsUseCase,
+			env.AccessTokenTTL,
+		),
+	))
+	deleteUserUseCase := usecases.NewLoggerUseCase(usecases.NewTracingUseCase(
+		usecases.NewDeleteUserUseCase(
+			databaseGateway,
+			cacheGateway,
+			findUserUseCase,
+			revokeUserSessionsUseCase,
+			env.AccessTokenTTL,
+		),
+	))
+	changePasswordUseCase := usecases.NewTracingUseCase(
+		usecases.NewChangePasswordUseCase(databaseGateway, revokeUserSessionsUseCase),
 	)
-	authenticateUserUseCase := usecases.NewAuthenticateUserUseCase(
-		databaseGateway,
-		env.MaestroSecretKey,
+	resetPasswordUseCase := usecases.NewTracingUseCase(
+		usecases.NewResetPasswordUseCase(databaseGateway, findUserUseCase, revokeUserSessionsUseCase),
 	)
-	setUpNodeUseCase := usecases.NewSetNodeUpUseCase(cacheGateway)
-	updateNodeUseCase := usecases.NewUpdateNodeUseCase(databaseGateway, cacheGateway)
 
-	createDefaultUser := usecases.NewCreateDefaultUserUseCase(databaseGateway, vpnGateway)
+	resetTwoFactorUseCase := usecases.NewLoggerUseCase(usecases.NewTracingUseCase(
+		usecases.NewResetTwoFactorUseCase(databaseGateway, findUserUseCase),
+	))
+
+	createDefaultUser := usecases.NewTracingUseCase(
+		usecases.NewCreateDefaultUserUseCase(databaseGateway, vpnGateway),
+	)
 
 	defaultUser := env.DefaultUser()
-	response, err := createDefaultUser.Execute(defaultUser)
+	response, err := createDefaultUser.Execute(ctx, defaultUser)
 	if err != nil {
 		panic(err)
 	}
@@ -84,8 +448,21 @@ func main() {
 			Msg("default user created")
 	}
 
+	listenAddress, err := listenAddress(env, vpnGateway)
+	if err != nil {
+		log.Fatal().Err(err).Msg("unable to resolve the listen address")
+	}
+
+	tlsConfig, err := tlsConfig(env, listenAddress)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid tls configuration")
+	}
+
 	maestro := server.NewMaestroServer(
 		env,
+		jwtKeySet,
+		listenAddress,
+		tlsConfig,
 		findNodesUseCase,
 		createNodeUseCase,
 		findNodeUseCase,
@@ -93,8 +470,179 @@ func main() {
 		setUpNodeUseCase,
 		nodeStatusService,
 		updateNodeUseCase,
+		broadcastUseCase,
+		execCommandUseCase,
+		findJobsUseCase,
+		findJobUseCase,
+		cancelJobUseCase,
+		openShellSessionUseCase,
+		findShellSessionsUseCase,
+		findShellSessionUseCase,
+		closeShellSessionUseCase,
+		listNodeFilesUseCase,
+		statNodeFileUseCase,
+		uploadNodeFileUseCase,
+		downloadNodeFileUseCase,
+		createScheduleUseCase,
+		findSchedulesUseCase,
+		findScheduleUseCase,
+		updateScheduleUseCase,
+		deleteScheduleUseCase,
+		findScheduleRunsUseCase,
+		findUsersUseCase,
+		findUserUseCase,
+		createUserUseCase,
+		updateUserUseCase,
+		deleteUserUseCase,
+		changePasswordUseCase,
+		resetPasswordUseCase,
+		deleteNodeUseCase,
+		canAccessNodeUseCase,
+		createNodeGroupUseCase,
+		findNodeGroupsUseCase,
+		findNodeGroupUseCase,
+		updateNodeGroupUseCase,
+		deleteNodeGroupUseCase,
+		logoutUseCase,
+		revokeUserSessionsUseCase,
+		isTokenRevokedUseCase,
+		refreshSessionUseCase,
+		findSessionsUseCase,
+		revokeSessionUseCase,
+		createApiKeyUseCase,
+		findApiKeysUseCase,
+		deleteApiKeyUseCase,
+		authenticateApiKeyUseCase,
+		startOidcLoginUseCase,
+		completeOidcLoginUseCase,
+		verifyTwoFactorUseCase,
+		enrollTotpUseCase,
+		confirmTotpUseCase,
+		disableTotpUseCase,
+		regenerateRecoveryCodesUseCase,
+		resetTwoFactorUseCase,
+		findAuthPolicyUseCase,
+		updateAuthPolicyUseCase,
+		recordAuditEventUseCase,
+		findAuditLogUseCase,
+		checkReadinessUseCase,
 	)
-	if err := maestro.Run(); err != nil {
-		panic(err)
+	runErr := maestro.Run(ctx)
+
+	// a second signal kills the process instead of waiting for the cleanup
+	stop()
+
+	closeCtx, cancel := context.WithTimeout(context.Background(), env.ShutdownTimeout)
+	defer cancel()
+
+	if err := shellSessionService.CloseAll(closeCtx, "server shutting down"); err != nil {
+		log.Warn().Err(err).Msg("shell sessions still open after shutdown timeout")
+	}
+
+	// jobs and schedule runs write their outcome to the database, so they
+	// have to stop before it is closed
+	if err := jobRegistryService.CancelAll(closeCtx); err != nil {
+		log.Warn().Err(err).Msg("jobs still running after shutdown timeout")
+	}
+
+	nodeStatusService.Close()
+
+	if err := cacheGateway.Close(); err != nil {
+		log.Error().Err(err).Msg("unable to close redis client")
+	}
+
+	databaseGateway.Close()
+
+	if env.WireguardDownOnShutdown {
+		if err := vpnGateway.Stop(); err != nil {
+			log.Error().Err(err).Msg("unable to bring wireguard down")
+		}
+	}
+
+	// flush last, so the spans of the cleanup above are exported too
+	if err := shutdownTracing(closeCtx); err != nil {
+		log.Error().Err(err).Msg("unable to flush traces")
+	}
+
+	if runErr != nil {
+		log.Fatal().Err(runErr).Msg("server stopped with an error")
+	}
+
+	log.Info().Msg("server stopped")
+}
+
+// oidcRoles validates the OIDC role settings. A default role of NONE refuses
+// users whose groups match no mapping.
+func oidcRoles(env config.Env) (map[string]dtos.Role, dtos.Role, error) {
+	roleMapping := map[string]dtos.Role{}
+	for group, role := range env.OidcRoleMapping {
+		if !dtos.Role(role).Valid() {
+			return nil, "", fmt.Errorf("invalid role %q mapped to oidc group %q", role, group)
+		}
+		roleMapping[group] = dtos.Role(role)
+	}
+
+	if env.OidcDefaultRole == "NONE" {
+		return roleMapping, "", nil
+	}
+
+	defaultRole := dtos.Role(env.OidcDefaultRole)
+	if !defaultRole.Valid() {
+		return nil, "", fmt.Errorf("invalid oidc default role %q", env.OidcDefaultRole)
+	}
+
+	return roleMapping, defaultRole, nil
+}
+
+// listenAddress returns LISTEN_ADDRESS, with the host replaced by wg0's
+// address when LISTEN_ON_WIREGUARD is set.
+func listenAddress(env config.Env, vpnGateway interfaces.IVpnGateway) (string, error) {
+	if !env.ListenOnWireguard {
+		return env.ListenAddress, nil
+	}
+
+	_, port, err := net.SplitHostPort(env.ListenAddress)
+	if err != nil {
+		return "", err
 	}
+
+	host, err := vpnGateway.InterfaceAddress()
+	if err != nil {
+		return "", err
+	}
+
+	return net.JoinHostPort(host, port), nil
+}
+
+// tlsConfig returns nil when TLS is off. A self-signed certificate is valid
+// for localhost, the listen host and the WireGuard endpoint host.
+func tlsConfig(env config.Env, listenAddress string) (*tls.Config, error) {
+	if !env.TLSEnabled() {
+		return nil, nil
+	}
+
+	if env.TLSSelfSigned {
+		hosts := []string{"localhost", "127.0.0.1", "::1"}
+		if host, _, err := net.SplitHostPort(listenAddress); err == nil {
+			hosts = append(hosts, host)
+		}
+		endpoint := strings.NewReplacer(`“`, "", `”`, "").Replace(env.WireguardEndpoint)
+		if host, _, err := net.SplitHostPort(endpoint); err == nil {
+			hosts = append(hosts, host)
+		}
+
+		if err := utils.GenerateSelfSignedCertificate(env.TLSCertFile, env.TLSKeyFile, hosts); err != nil {
+			return nil, err
+		}
+	}
+
+	reloader, err := utils.NewCertificateReloader(env.TLSCertFile, env.TLSKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.GetCertificate,
+	}, nil
 }
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/JMCDynamics/maestro-server/internal/adapters"
+	"github.com/JMCDynamics/maestro-server/internal/config"
+	"github.com/JMCDynamics/maestro-server/migrations"
+)
+
+const migrateUsage = "usage: maestro-server migrate up | down [N] | status | force VERSION"
+
+// migrateCommand runs `migrate up`, `migrate down [N]` (one step unless N is
+// given), `migrate status` or `migrate force VERSION` against the configured
+// database.
+func migrateCommand(env config.Env, args []string) error {
+	command, n, err := parseMigrateArgs(args)
+	if err != nil {
+		return err
+	}
+
+	migrationGateway, err := adapters.NewMigrationGateway(env.Database.UrlConnection(), migrations.FS)
+	if err != nil {
+		return err
+	}
+	defer migrationGateway.Close()
+
+	switch command {
+	case "up":
+		err = migrationGateway.Up()
+	case "down":
+		err = migrationGateway.Down(n)
+	case "force":
+		err = migrationGateway.Force(n)
+	}
+	if err != nil {
+		return err
+	}
+
+	status, err := migrationGateway.Status()
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range status.Migrations {
+		state := "pending"
+		if migration.Applied {
+			state = "applied"
+		}
+		if status.Dirty && migration.Version == status.Version {
+			state = "dirty"
+		}
+
+		fmt.Printf("%06d  %-8s %s\n", migration.Version, state, migration.Name)
+	}
+
+	if status.Dirty {
+		fmt.Printf("\nschema version %d is dirty: repair it, then run `migrate force VERSION`\n", status.Version)
+	} else {
+		fmt.Printf("\nschema version %d\n", status.Version)
+	}
+
+	return nil
+}
+
+// parseMigrateArgs returns the subcommand and its number: the steps for
+// down, the version for force.
+func parseMigrateArgs(args []string) (string, int, error) {
+	switch {
+	case len(args) == 1 && (args[0] == "up" || args[0] == "status"):
+		return args[0], 0, nil
+
+	case len(args) == 1 && args[0] == "down":
+		return "down", 1, nil
+
+	case len(args) == 2 && args[0] == "down":
+		steps, err := strconv.Atoi(args[1])
+		if err != nil || steps < 1 {
+			return "", 0, fmt.Errorf("invalid number of migrations %q", args[1])
+		}
+		return "down", steps, nil
+
+	case len(args) == 2 && args[0] == "force":
+		version, err := strconv.Atoi(args[1])
+		if err != nil || version < -1 {
+			return "", 0, fmt.Errorf("invalid version %q", args[1])
+		}
+		return "force", version, nil
+	}
+
+	return "", 0, errors.New(migrateUsage)
+}
@@ -0,0 +1,118 @@
+package adapters
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/golang-migrate/migrate/v4"
+	pgxmigrate "github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// migrationLockTimeout bounds how long a server waits for another instance
+// to finish migrating before giving up.
+const migrationLockTimeout = 5 * time.Minute
+
+type golangMigrateAdapter struct {
+	migrate *migrate.Migrate
+	source  source.Driver
+}
+
+// NewMigrationGateway migrates the database at connString with the
+// migrations in migrations, a directory of golang-migrate
+// <version>_<name>.up.sql / .down.sql files. The version is kept in the
+// schema_migrations table, as the migrate CLI does.
+func NewMigrationGateway(connString string, migrations fs.FS) (interfaces.IMigrationGateway, error) {
+	config, err := pgxpool.ParseConfig(connString)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	sourceDriver, err := iofs.New(migrations, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	// pgxpool strips the pool_* settings the plain connection does not know.
+	db := stdlib.OpenDB(*config.ConnConfig)
+	databaseDriver, err := pgxmigrate.WithInstance(db, &pgxmigrate.Config{})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create driver instance: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", sourceDriver, "pgx5", databaseDriver)
+	if err != nil {
+		databaseDriver.Close()
+		return nil, fmt.Errorf("failed to create migration instance: %w", err)
+	}
+	m.LockTimeout = migrationLockTimeout
+
+	return &golangMigrateAdapter{migrate: m, source: sourceDriver}, nil
+}
+
+func (g *golangMigrateAdapter) Up() error {
+	if err := g.migrate.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+	return nil
+}
+
+func (g *golangMigrateAdapter) Down(steps int) error {
+	if err := g.migrate.Steps(-steps); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to roll back migrations: %w", err)
+	}
+	return nil
+}
+
+func (g *golangMigrateAdapter) Force(version int) error {
+	if err := g.migrate.Force(version); err != nil {
+		return fmt.Errorf("failed to force version: %w", err)
+	}
+	return nil
+}
+
+func (g *golangMigrateAdapter) Status() (dtos.MigrationStatus, error) {
+	status := dtos.MigrationStatus{Migrations: []dtos.Migration{}}
+
+	version, dirty, err := g.migrate.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return status, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	status.Version = version
+	status.Dirty = dirty
+
+	next, err := g.source.First()
+	for err == nil {
+		r, name, readErr := g.source.ReadUp(next)
+		if readErr != nil {
+			return status, fmt.Errorf("failed to read migration %d: %w", next, readErr)
+		}
+		r.Close()
+
+		status.Migrations = append(status.Migrations, dtos.Migration{
+			Version: next,
+			Name:    name,
+			Applied: next < version || (next == version && !dirty),
+		})
+
+		next, err = g.source.Next(next)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return status, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	return status, nil
+}
+
+func (g *golangMigrateAdapter) Close() error {
+	sourceErr, databaseErr := g.migrate.Close()
+	return errors.Join(sourceErr, databaseErr)
+}
@@ -0,0 +1,125 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+type OidcAdapter struct {
+	issuerURL     string
+	clientId      string
+	clientSecret  string
+	redirectURL   string
+	scopes        []string
+	usernameClaim string
+	groupsClaim   string
+	m             sync.Mutex
+	provider      *oidc.Provider
+}
+
+func NewOidcAdapter(
+	issuerURL, clientId, clientSecret, redirectURL string,
+	scopes []string,
+	usernameClaim, groupsClaim string,
+) interfaces.IIdentityProviderGateway {
+	return &OidcAdapter{
+		issuerURL:     issuerURL,
+		clientId:      clientId,
+		clientSecret:  clientSecret,
+		redirectURL:   redirectURL,
+		scopes:        scopes,
+		usernameClaim: usernameClaim,
+		groupsClaim:   groupsClaim,
+	}
+}
+
+// discover fetches the provider metadata on first use, so the server starts
+// even while the identity provider is unreachable.
+func (o *OidcAdapter) discover(ctx context.Context) (*oidc.Provider, oauth2.Config, error) {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	if o.provider == nil {
+		provider, err := oidc.NewProvider(ctx, o.issuerURL)
+		if err != nil {
+			return nil, oauth2.Config{}, fmt.Errorf("unable to discover oidc provider: %v", err)
+		}
+		o.provider = provider
+	}
+
+	return o.provider, oauth2.Config{
+		ClientID:     o.clientId,
+		ClientSecret: o.clientSecret,
+		RedirectURL:  o.redirectURL,
+		Endpoint:     o.provider.Endpoint(),
+		Scopes:       o.scopes,
+	}, nil
+}
+
+func (o *OidcAdapter) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	_, config, err := o.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return config.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(codeVerifier)), nil
+}
+
+func (o *OidcAdapter) Exchange(ctx context.Context, code, nonce, codeVerifier string) (dtos.ExternalIdentity, error) {
+	provider, config, err := o.discover(ctx)
+	if err != nil {
+		return dtos.ExternalIdentity{}, err
+	}
+
+	token, err := config.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return dtos.ExternalIdentity{}, fmt.Errorf("unable to exchange code: %v", err)
+	}
+
+	rawIdToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return dtos.ExternalIdentity{}, errors.New("token response has no id_token")
+	}
+
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: o.clientId}).Verify(ctx, rawIdToken)
+	if err != nil {
+		return dtos.ExternalIdentity{}, fmt.Errorf("invalid id_token: %v", err)
+	}
+
+	if idToken.Nonce != nonce {
+		return dtos.ExternalIdentity{}, errors.New("id_token nonce does not match")
+	}
+
+	var claims map[string]any
+	if err := idToken.Claims(&claims); err != nil {
+		return dtos.ExternalIdentity{}, fmt.Errorf("unable to read id_token claims: %v", err)
+	}
+
+	identity := dtos.ExternalIdentity{
+		Issuer:  idToken.Issuer,
+		Subject: idToken.Subject,
+		Groups:  []string{},
+	}
+	identity.Username, _ = claims[o.usernameClaim].(string)
+	identity.Email, _ = claims["email"].(string)
+
+	switch groups := claims[o.groupsClaim].(type) {
+	case []any:
+		for _, group := range groups {
+			if name, ok := group.(string); ok {
+				identity.Groups = append(identity.Groups, name)
+			}
+		}
+	case string:
+		identity.Groups = append(identity.Groups, groups)
+	}
+
+	return identity, nil
+}
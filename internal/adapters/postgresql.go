@@ -3,14 +3,16 @@ package adapters
 import (
 	"context"
 	"fmt"
-	"time"
+	"strings"
 
 	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/JMCDynamics/maestro-server/internal/utils"
+	"github.com/JMCDynamics/maestro-server/migrations"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
-
-	_ "github.com/golang-migrate/migrate/v4/source/file"
-	_ "github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type postgreDatabaseAdapter struct {
@@ -38,15 +40,14 @@ func (r *PGXResultRow) Err() error {
 	return r.rows.Err()
 }
 
+// NewDatabaseGateway opens a pool for connString. Pool sizing comes from the
+// connection string's pool_* parameters.
 func NewDatabaseGateway(connString string) (interfaces.IDatabaseGateway, error) {
 	config, err := pgxpool.ParseConfig(connString)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse config: %w", err)
 	}
-
-	config.MaxConns = 10
-	config.MinConns = 2
-	config.MaxConnLifetime = time.Hour
+	config.ConnConfig.Tracer = queryTracer{}
 
 	pool, err := pgxpool.NewWithConfig(context.Background(), config)
 	if err != nil {
@@ -56,8 +57,70 @@ func NewDatabaseGateway(connString string) (interfaces.IDatabaseGateway, error)
 	return &postgreDatabaseAdapter{pool: pool, connectionString: connString}, nil
 }
 
+// queryTracer runs every query in a client span named after its SQL
+// operation. Arguments are left out of the span, only the statement is kept.
+type queryTracer struct{}
+
+func (queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	operation, _, _ := strings.Cut(strings.TrimSpace(data.SQL), " ")
+	operation = strings.ToUpper(operation)
+
+	ctx, _ = utils.Tracer().Start(ctx, operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBOperationName(operation),
+			semconv.DBQueryText(data.SQL),
+		),
+	)
+
+	return ctx
+}
+
+func (queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	utils.EndSpan(trace.SpanFromContext(ctx), data.Err)
+}
+
+// querier is what the pool and a transaction have in common.
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+type transactionKey struct{}
+
+// querier returns the transaction started by Transaction in ctx, or the pool.
+func (pg *postgreDatabaseAdapter) querier(ctx context.Context) querier {
+	if tx, ok := ctx.Value(transactionKey{}).(pgx.Tx); ok {
+		return tx
+	}
+
+	return pg.pool
+}
+
+// Transaction runs fn in a transaction that every call made with the ctx it
+// receives joins. It commits when fn returns nil and rolls back otherwise.
+func (pg *postgreDatabaseAdapter) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(transactionKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := pg.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(context.WithoutCancel(ctx))
+
+	if err := fn(context.WithValue(ctx, transactionKey{}, tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
 func (pg *postgreDatabaseAdapter) Query(ctx context.Context, query string, args ...any) (interfaces.ResultSet, error) {
-	rows, err := pg.pool.Query(ctx, query, args...)
+	rows, err := pg.querier(ctx).Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -67,44 +130,32 @@ func (pg *postgreDatabaseAdapter) Query(ctx context.Context, query string, args
 }
 
 func (pg *postgreDatabaseAdapter) Exec(ctx context.Context, query string, args ...any) error {
-	_, err := pg.pool.Exec(ctx, query, args...)
+	_, err := pg.querier(ctx).Exec(ctx, query, args...)
 	return err
 }
 
 func (pg *postgreDatabaseAdapter) QueryRow(ctx context.Context, query string, dest any, args ...any) error {
-	row := pg.pool.QueryRow(ctx, query, args...)
-	return row.Scan(&dest)
+	row := pg.querier(ctx).QueryRow(ctx, query, args...)
+	return row.Scan(dest)
+}
+
+func (pg *postgreDatabaseAdapter) Ping(ctx context.Context) error {
+	return pg.pool.Ping(ctx)
 }
 
 func (pg *postgreDatabaseAdapter) Close() {
 	pg.pool.Close()
 }
 
+// RunMigrations applies the migrations embedded in the binary. It is safe
+// to call from several servers at once: they take turns through a Postgres
+// advisory lock and the later ones find nothing left to do.
 func (pg *postgreDatabaseAdapter) RunMigrations() error {
-	// db, err := sql.Open("postgres", pg.connectionString)
-	// if err != nil {
-	// 	return fmt.Errorf("failed to open database: %w", err)
-	// }
-	// defer db.Close()
-
-	// driver, err := postgres.WithInstance(db, &postgres.Config{})
-	// if err != nil {
-	// 	return fmt.Errorf("failed to create driver instance: %w", err)
-	// }
-
-	// m, err := migrate.(
-	// 	"file://../../migrations",
-	// 	driver,
-	// 	pg.connectionString,
-	// )
-
-	// if err != nil {
-	// 	return fmt.Errorf("failed to create migration instance: %w", err)
-	// }
-
-	// if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-	// 	return fmt.Errorf("failed to run migrations: %w", err)
-	// }
-
-	return nil
+	migrationGateway, err := NewMigrationGateway(pg.connectionString, migrations.FS)
+	if err != nil {
+		return err
+	}
+	defer migrationGateway.Close()
+
+	return migrationGateway.Up()
 }
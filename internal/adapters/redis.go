@@ -2,29 +2,42 @@ package adapters
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/JMCDynamics/maestro-server/internal/utils"
 	"github.com/go-redis/redis/v8"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type RedisCacheAdapter struct {
 	client            *redis.Client
+	pubsub            *redis.PubSub
 	expiredKeyChannel chan string
 }
 
-func NewRedisCacheAdapter(addr, password string, db int) interfaces.ICacheGateway {
+// NewRedisCacheAdapter connects to Redis; tlsConfig is nil for plain TCP.
+func NewRedisCacheAdapter(addr, username, password string, db, poolSize int, tlsConfig *tls.Config) interfaces.ICacheGateway {
 	rdb := redis.NewClient(&redis.Options{
-		Addr:     addr,
-		Password: password,
-		DB:       db,
+		Addr:      addr,
+		Username:  username,
+		Password:  password,
+		DB:        db,
+		PoolSize:  poolSize,
+		TLSConfig: tlsConfig,
 	})
+	rdb.AddHook(redisTracingHook{})
 
 	expiredKeyChannel := make(chan string)
 	pubsub := rdb.PSubscribe(context.Background(), fmt.Sprintf("__keyevent@%d__:expired", db))
 
 	go func() {
+		defer close(expiredKeyChannel)
+
 		for msg := range pubsub.Channel() {
 			nodeID := msg.Payload
 			expiredKeyChannel <- nodeID
@@ -33,22 +46,166 @@ func NewRedisCacheAdapter(addr, password string, db int) interfaces.ICacheGatewa
 
 	return &RedisCacheAdapter{
 		client:            rdb,
+		pubsub:            pubsub,
 		expiredKeyChannel: expiredKeyChannel,
 	}
 }
 
+// redisTracingHook runs every command, or pipeline, in a client span. Only
+// the command name is recorded, keys and values may hold tokens.
+type redisTracingHook struct{}
+
+func (redisTracingHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	ctx, _ = utils.Tracer().Start(ctx, cmd.FullName(),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemRedis, semconv.DBOperationName(cmd.FullName())),
+	)
+
+	return ctx, nil
+}
+
+func (redisTracingHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	endRedisSpan(ctx, cmd)
+	return nil
+}
+
+func (redisTracingHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	ctx, _ = utils.Tracer().Start(ctx, "pipeline",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemRedis, semconv.DBOperationName("pipeline")),
+	)
+
+	return ctx, nil
+}
+
+func (redisTracingHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	for _, cmd := range cmds {
+		if cmd.Err() != nil && cmd.Err() != redis.Nil {
+			endRedisSpan(ctx, cmd)
+			return nil
+		}
+	}
+
+	endRedisSpan(ctx, nil)
+	return nil
+}
+
+// endRedisSpan ends the span in ctx, failing it with cmd's error unless the
+// key was simply missing.
+func endRedisSpan(ctx context.Context, cmd redis.Cmder) {
+	var err error
+	if cmd != nil && cmd.Err() != redis.Nil {
+		err = cmd.Err()
+	}
+
+	utils.EndSpan(trace.SpanFromContext(ctx), err)
+}
+
 func (r *RedisCacheAdapter) Get(ctx context.Context, key string) (string, error) {
-	return r.client.Get(ctx, key).Result()
+	value, err := r.client.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return "", interfaces.ErrKeyNotFound
+	}
+
+	return value, err
 }
 
 func (r *RedisCacheAdapter) Set(ctx context.Context, key string, value string, expiration time.Duration) error {
 	return r.client.Set(ctx, key, value, expiration).Err()
 }
 
+func (r *RedisCacheAdapter) SetIfNotExists(ctx context.Context, key string, value string, expiration time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, key, value, expiration).Result()
+}
+
+func (r *RedisCacheAdapter) Increment(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	var incr *redis.IntCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, key)
+		pipe.Expire(ctx, key, expiration)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return incr.Val(), nil
+}
+
+func (r *RedisCacheAdapter) Decrement(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	var decr *redis.IntCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		decr = pipe.Decr(ctx, key)
+		pipe.Expire(ctx, key, expiration)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return decr.Val(), nil
+}
+
+func (r *RedisCacheAdapter) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := r.client.PTTL(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	// go-redis passes the -2 "no such key" reply through unscaled
+	if ttl == -2 {
+		return 0, interfaces.ErrKeyNotFound
+	}
+
+	return ttl, nil
+}
+
 func (r *RedisCacheAdapter) Delete(ctx context.Context, key string) error {
 	return r.client.Del(ctx, key).Err()
 }
 
+// deleteIfEqualsScript compares and deletes in one step, so a key that
+// expired and was taken by someone else in between is left alone.
+var deleteIfEqualsScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+func (r *RedisCacheAdapter) DeleteIfEquals(ctx context.Context, key string, value string) (bool, error) {
+	deleted, err := deleteIfEqualsScript.Run(ctx, r.client, []string{key}, value).Int()
+	if err != nil {
+		return false, err
+	}
+
+	return deleted == 1, nil
+}
+
+func (r *RedisCacheAdapter) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
+func (r *RedisCacheAdapter) NotifyKeyspaceEvents(ctx context.Context) (string, error) {
+	values, err := r.client.ConfigGet(ctx, "notify-keyspace-events").Result()
+	if err != nil {
+		return "", err
+	}
+
+	if len(values) != 2 {
+		return "", errors.New("notify-keyspace-events is not set")
+	}
+
+	flags, _ := values[1].(string)
+	return flags, nil
+}
+
 func (r *RedisCacheAdapter) ListenExpiredKeys() <-chan string {
 	return r.expiredKeyChannel
 }
+
+// Close ends the expired key subscription, which closes the channel returned
+// by ListenExpiredKeys, and the client.
+func (r *RedisCacheAdapter) Close() error {
+	return errors.Join(r.pubsub.Close(), r.client.Close())
+}
@@ -2,8 +2,10 @@ package adapters
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"fmt"
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -12,6 +14,9 @@ import (
 
 	"github.com/JMCDynamics/maestro-server/internal/dtos"
 	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/JMCDynamics/maestro-server/internal/utils"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Wireguard struct {
@@ -32,10 +37,63 @@ func NewWireguardAdapter(endpoint string) interfaces.IVpnGateway {
 
 func (w *Wireguard) Run() error {
 	cmd := exec.Command("wg-quick", "up", "/config/wg_confs/wg0.conf")
-	return cmd.Run()
+	_, err := tracedCommand(context.Background(), cmd, cmd.Output)
+	return err
 }
 
-func (w *Wireguard) GenerateNewPeer(name string) (dtos.ResponseNewPeer, error) {
+func (w *Wireguard) Stop() error {
+	cmd := exec.Command("wg-quick", "down", path_to_conf)
+	output, err := tracedCommand(context.Background(), cmd, cmd.CombinedOutput)
+	if err != nil {
+		return fmt.Errorf("unable to bring wg0 down: %v\noutput: %s", err, string(output))
+	}
+
+	return nil
+}
+
+func (w *Wireguard) CheckInterface() error {
+	iface, err := net.InterfaceByName("wg0")
+	if err != nil {
+		return fmt.Errorf("wg0 not found: %v", err)
+	}
+
+	if iface.Flags&net.FlagUp == 0 {
+		return errors.New("wg0 is down")
+	}
+
+	return nil
+}
+
+func (w *Wireguard) InterfaceAddress() (string, error) {
+	iface, err := net.InterfaceByName("wg0")
+	if err != nil {
+		return "", fmt.Errorf("wg0 not found: %v", err)
+	}
+
+	addresses, err := iface.Addrs()
+	if err != nil {
+		return "", fmt.Errorf("unable to read wg0 addresses: %v", err)
+	}
+
+	for _, address := range addresses {
+		if ipNet, ok := address.(*net.IPNet); ok && ipNet.IP.To4() != nil {
+			return ipNet.IP.String(), nil
+		}
+	}
+
+	return "", errors.New("wg0 has no IPv4 address")
+}
+
+func (w *Wireguard) CheckConfig() error {
+	file, err := os.Open(path_to_conf)
+	if err != nil {
+		return fmt.Errorf("unable to read the wg0 conf file: %v", err)
+	}
+
+	return file.Close()
+}
+
+func (w *Wireguard) GenerateNewPeer(ctx context.Context, name string) (dtos.ResponseNewPeer, error) {
 	nextAddress, err := getNextAddress()
 	if err != nil {
 		return dtos.ResponseNewPeer{}, err
@@ -46,7 +104,7 @@ func (w *Wireguard) GenerateNewPeer(name string) (dtos.ResponseNewPeer, error) {
 		return dtos.ResponseNewPeer{}, fmt.Errorf("unable to create peer's folder: %v", err)
 	}
 
-	privateKey, publicKey, presharedKey, err := generateKeys(name)
+	privateKey, publicKey, presharedKey, err := generateKeys(ctx, name)
 	if err != nil {
 		return dtos.ResponseNewPeer{}, err
 	}
@@ -56,7 +114,7 @@ func (w *Wireguard) GenerateNewPeer(name string) (dtos.ResponseNewPeer, error) {
 		return dtos.ResponseNewPeer{}, err
 	}
 
-	if err := addPeerToServer(name, publicKey, presharedKey, nextAddress); err != nil {
+	if err := addPeerToServer(ctx, name, publicKey, presharedKey, nextAddress); err != nil {
 		return dtos.ResponseNewPeer{}, err
 	}
 
@@ -66,6 +124,67 @@ func (w *Wireguard) GenerateNewPeer(name string) (dtos.ResponseNewPeer, error) {
 	}, nil
 }
 
+func (w *Wireguard) RemovePeer(ctx context.Context, name string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	peerPath := fmt.Sprintf("%s/peer_%s", path_to_peers, name)
+
+	publicKey, err := os.ReadFile(fmt.Sprintf("%s/publickey-peer_%s", peerPath, name))
+	if err != nil {
+		return fmt.Errorf("unable to read peer's public key: %v", err)
+	}
+
+	cmd := exec.Command("wg", "set", "wg0", "peer", strings.TrimSpace(string(publicKey)), "remove")
+	output, err := tracedCommand(ctx, cmd, cmd.CombinedOutput)
+	if err != nil {
+		return fmt.Errorf("unable to remove peer: %v\noutput: %s", err, string(output))
+	}
+
+	if err := removePeerFromServerConf(name); err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(peerPath); err != nil {
+		return fmt.Errorf("unable to remove peer's folder: %v", err)
+	}
+
+	return nil
+}
+
+// removePeerFromServerConf drops the [Peer] block tagged "# peer_<name>" from
+// wg0.conf, leaving every other section untouched.
+func removePeerFromServerConf(peerName string) error {
+	content, err := os.ReadFile(path_to_conf)
+	if err != nil {
+		return errors.New("unable to open the wg0 conf file")
+	}
+
+	lines := strings.Split(string(content), "\n")
+	kept := make([]string, 0, len(lines))
+	skipping := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			skipping = trimmed == "[Peer]" && i+1 < len(lines) && strings.TrimSpace(lines[i+1]) == "# peer_"+peerName
+			if skipping && len(kept) > 0 && strings.TrimSpace(kept[len(kept)-1]) == "" {
+				kept = kept[:len(kept)-1]
+			}
+		}
+
+		if !skipping {
+			kept = append(kept, line)
+		}
+	}
+
+	if err := os.WriteFile(path_to_conf, []byte(strings.Join(kept, "\n")), 0644); err != nil {
+		return fmt.Errorf("unable to write wg conf: %v", err)
+	}
+
+	return nil
+}
+
 func getNextAddress() (string, error) {
 	file, err := os.Open(path_to_conf)
 	if err != nil {
@@ -113,9 +232,9 @@ func getServerPublicKey() (string, error) {
 	return strings.TrimSpace(string(key)), nil
 }
 
-func generateKeys(peerName string) (string, string, string, error) {
-	privateKeyCmd := exec.Command("wg", "genkey")
-	privateKey, err := privateKeyCmd.Output()
+func generateKeys(ctx context.Context, peerName string) (string, string, string, error) {
+	privateKeyCmd := exec.CommandContext(ctx, "wg", "genkey")
+	privateKey, err := tracedCommand(ctx, privateKeyCmd, privateKeyCmd.Output)
 	if err != nil {
 		return "", "", "", err
 	}
@@ -125,9 +244,9 @@ func generateKeys(peerName string) (string, string, string, error) {
 		return "", "", "", fmt.Errorf("failed to save private key: %v", err)
 	}
 
-	publicKeyCmd := exec.Command("wg", "pubkey")
+	publicKeyCmd := exec.CommandContext(ctx, "wg", "pubkey")
 	publicKeyCmd.Stdin = strings.NewReader(privateKeyStr)
-	publicKey, err := publicKeyCmd.Output()
+	publicKey, err := tracedCommand(ctx, publicKeyCmd, publicKeyCmd.Output)
 	if err != nil {
 		return "", "", "", fmt.Errorf("failed to generate public key: %v", err)
 	}
@@ -137,8 +256,8 @@ func generateKeys(peerName string) (string, string, string, error) {
 		return "", "", "", fmt.Errorf("failed to save public key: %v", err)
 	}
 
-	presharedKeyCmd := exec.Command("wg", "genkey")
-	presharedKey, err := presharedKeyCmd.Output()
+	presharedKeyCmd := exec.CommandContext(ctx, "wg", "genkey")
+	presharedKey, err := tracedCommand(ctx, presharedKeyCmd, presharedKeyCmd.Output)
 	if err != nil {
 		return "", "", "", err
 	}
@@ -199,7 +318,7 @@ Endpoint = %s`,
 	return config, nil
 }
 
-func addPeerToServer(peerName, publicKey, presharedKey, peerAddress string) error {
+func addPeerToServer(ctx context.Context, peerName, publicKey, presharedKey, peerAddress string) error {
 	peerConfig := fmt.Sprintf("\n[Peer]\n# peer_%s\nPublicKey = %s\nPresharedKey = %s\nAllowedIPs = %s/32",
 		peerName,
 		publicKey,
@@ -220,35 +339,52 @@ func addPeerToServer(peerName, publicKey, presharedKey, peerAddress string) erro
 	presharedKeyPath := fmt.Sprintf("%s/peer_%s/presharedkey-peer_%s", path_to_peers, peerName, peerName)
 	cmd := exec.Command("wg", "set", "wg0", "peer", publicKey, "preshared-key", presharedKeyPath, "allowed-ips", peerAddress+"/32")
 
-	output, err := cmd.CombinedOutput()
+	output, err := tracedCommand(ctx, cmd, cmd.CombinedOutput)
 	if err != nil {
 		return fmt.Errorf("unable to add new peer: %v\noutput: %s", err, string(output))
 	}
 
 	cmd = exec.Command("ip", "-4", "route", "add", peerAddress+"/32", "dev", "wg0")
 
-	output, err = cmd.CombinedOutput()
+	output, err = tracedCommand(ctx, cmd, cmd.CombinedOutput)
 	if err != nil {
 		return fmt.Errorf("unable to add new peer: %v\noutput: %s", err, string(output))
 	}
 
 	cmd = exec.Command("iptables", "-A", "FORWARD", "-i", "wg0", "-j", "ACCEPT")
-	output, err = cmd.CombinedOutput()
+	output, err = tracedCommand(ctx, cmd, cmd.CombinedOutput)
 	if err != nil {
 		return fmt.Errorf("erro ao adicionar regra FORWARD de entrada: %v\noutput: %s", err, string(output))
 	}
 
 	cmd = exec.Command("iptables", "-A", "FORWARD", "-o", "wg0", "-j", "ACCEPT")
-	output, err = cmd.CombinedOutput()
+	output, err = tracedCommand(ctx, cmd, cmd.CombinedOutput)
 	if err != nil {
 		return fmt.Errorf("erro ao adicionar regra FORWARD de saída: %v\noutput: %s", err, string(output))
 	}
 
 	cmd = exec.Command("iptables", "-t", "nat", "-A", "POSTROUTING", "-o", "eth+", "-j", "MASQUERADE")
-	output, err = cmd.CombinedOutput()
+	output, err = tracedCommand(ctx, cmd, cmd.CombinedOutput)
 	if err != nil {
 		return fmt.Errorf("erro ao adicionar regra de NAT: %v\noutput: %s", err, string(output))
 	}
 
 	return nil
 }
+
+// tracedCommand runs cmd through run, one of its Output methods, inside a
+// span named after the program and subcommand, e.g. "wg set". Only the
+// arguments are recorded, never stdin, which may carry a private key.
+func tracedCommand(ctx context.Context, cmd *exec.Cmd, run func() ([]byte, error)) ([]byte, error) {
+	_, span := utils.Tracer().Start(ctx, strings.Join(cmd.Args[:min(len(cmd.Args), 2)], " "),
+		trace.WithAttributes(
+			semconv.ProcessCommand(cmd.Args[0]),
+			semconv.ProcessCommandArgs(cmd.Args...),
+		),
+	)
+
+	output, err := run()
+	utils.EndSpan(span, err)
+
+	return output, err
+}
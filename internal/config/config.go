@@ -1,52 +1,190 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"net"
+	"time"
 
 	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/utils"
+	"github.com/ardanlabs/conf/v3"
 )
 
-type Database struct {
-	DatabaseHost     string
-	DatabasePort     string
-	DatabaseUser     string
-	DatabasePassword string
-	DatabaseName     string
+const (
+	ENV_DEVELOPMENT = "development"
+	ENV_PRODUCTION  = "production"
 
-	RedisHost     string
-	RedisPort     string
-	RedisPassword string
+	defaultSecretKey = "maestro_key_dev"
+)
+
+// Env is parsed by ardanlabs/conf from the environment or command line flags;
+// run the server with --help for the full list. Masked settings are secrets
+// and may instead be read from a file named by the same variable suffixed
+// with _FILE.
+type Env struct {
+	Database Database
+	Redis    Redis
+	Tracing  Tracing
+
+	// ListenAddress is the API's host:port. With ListenOnWireguard only the
+	// host is replaced by wg0's address, keeping the API off other networks.
+	ListenAddress     string `conf:"env:LISTEN_ADDRESS,default::6276"`
+	ListenOnWireguard bool   `conf:"env:LISTEN_ON_WIREGUARD,default:false"`
+
+	// TrustedProxies lists the addresses or CIDRs ("10.0.0.0/8;10.1.2.3") of
+	// reverse proxies whose X-Forwarded-For is believed. Without any, the
+	// client IP used for login throttling and the audit log is the peer's.
+	TrustedProxies []string `conf:"env:TRUSTED_PROXIES"`
+
+	// CorsAllowedOrigins lists the browser origins ("https://a.example;
+	// https://b.example") allowed to call the API. Shell WebSockets are only
+	// accepted from these origins or the API's own host, never from "*".
+	CorsAllowedOrigins []string `conf:"env:CORS_ALLOWED_ORIGINS,default:*"`
+
+	// The API serves HTTPS when TLS_CERT_FILE and TLS_KEY_FILE are set; the
+	// files are reloaded when they change. TLS_SELF_SIGNED generates them on
+	// first boot if they do not exist.
+	TLSCertFile   string `conf:"env:TLS_CERT_FILE"`
+	TLSKeyFile    string `conf:"env:TLS_KEY_FILE"`
+	TLSSelfSigned bool   `conf:"env:TLS_SELF_SIGNED,default:false"`
+
+	WireguardEndpoint string `conf:"env:WIREGUARD_ENDPOINT"`
+
+	// WireguardDownOnShutdown runs `wg-quick down` on wg0 when the server
+	// stops. Leave it off when the interface is managed by the container.
+	WireguardDownOnShutdown bool `conf:"env:WIREGUARD_DOWN_ON_SHUTDOWN,default:false"`
+
+	// ShutdownTimeout bounds how long a stopping server waits for in-flight
+	// requests and shell sessions before closing them.
+	ShutdownTimeout time.Duration `conf:"env:SHUTDOWN_TIMEOUT,default:30s"`
+
+	MaestroUsername string `conf:"env:MAESTRO_USERNAME,default:maestro"`
+	MaestroPassword string `conf:"env:MAESTRO_PASSWORD,default:root,mask"`
+
+	// Environment is "development" or "production". Production refuses
+	// insecure defaults.
+	Environment string `conf:"env:MAESTRO_ENV,default:development"`
+
+	// MaestroSecretKey signs access tokens with HS256 unless JWT_KEYS is
+	// set. JWT_KEYS maps key ids to PEM files ("kid:/path;kid:/path") holding
+	// Ed25519 or RSA keys, e.g. from `openssl genpkey -algorithm ed25519`.
+	// JWT_ACTIVE_KEY names the private key that signs; the others only verify,
+	// and may be public keys. To rotate, add the new key, make it active and
+	// drop the old one once ACCESS_TOKEN_TTL has passed.
+	MaestroSecretKey string            `conf:"env:MAESTRO_SECRET_KEY,default:maestro_key_dev,mask"`
+	JwtKeys          map[string]string `conf:"env:JWT_KEYS"`
+	JwtActiveKey     string            `conf:"env:JWT_ACTIVE_KEY"`
+
+	AccessTokenTTL  time.Duration `conf:"env:ACCESS_TOKEN_TTL,default:15m"`
+	RefreshTokenTTL time.Duration `conf:"env:REFRESH_TOKEN_TTL,default:168h"`
+
+	LoginMaxAttemptsPerUser int           `conf:"env:LOGIN_MAX_ATTEMPTS_PER_USER,default:5"`
+	LoginMaxAttemptsPerIp   int           `conf:"env:LOGIN_MAX_ATTEMPTS_PER_IP,default:20"`
+	LoginAttemptWindow      time.Duration `conf:"env:LOGIN_ATTEMPT_WINDOW,default:15m"`
+	LoginLockoutBase        time.Duration `conf:"env:LOGIN_LOCKOUT_BASE,default:1m"`
+	LoginLockoutMax         time.Duration `conf:"env:LOGIN_LOCKOUT_MAX,default:1h"`
+
+	// TotpIssuer is the account name authenticator apps show for Maestro.
+	TotpIssuer string `conf:"env:TOTP_ISSUER,default:Maestro"`
+
+	// OIDC single sign-on is enabled when OIDC_ISSUER_URL is set. Role
+	// mapping is "group:ROLE;group:ROLE"; users matching no group get
+	// OIDC_DEFAULT_ROLE, or are refused when it is NONE.
+	OidcIssuerURL     string            `conf:"env:OIDC_ISSUER_URL"`
+	OidcClientId      string            `conf:"env:OIDC_CLIENT_ID"`
+	OidcClientSecret  string            `conf:"env:OIDC_CLIENT_SECRET,mask"`
+	OidcRedirectURL   string            `conf:"env:OIDC_REDIRECT_URL"`
+	OidcScopes        []string          `conf:"env:OIDC_SCOPES,default:openid;profile;email;groups"`
+	OidcUsernameClaim string            `conf:"env:OIDC_USERNAME_CLAIM,default:preferred_username"`
+	OidcGroupsClaim   string            `conf:"env:OIDC_GROUPS_CLAIM,default:groups"`
+	OidcRoleMapping   map[string]string `conf:"env:OIDC_ROLE_MAPPING"`
+	OidcDefaultRole   string            `conf:"env:OIDC_DEFAULT_ROLE,default:VIEWER"`
+	OidcPostLoginURL  string            `conf:"env:OIDC_POST_LOGIN_URL"`
+
+	ShellIdleTimeout    time.Duration `conf:"env:SHELL_IDLE_TIMEOUT,default:15m"`
+	ShellRecordingsPath string        `conf:"env:SHELL_RECORDINGS_PATH,default:/config/recordings"`
+
+	MaxFileSize int64 `conf:"env:MAX_FILE_SIZE,default:1073741824"`
+
+	SchedulerInterval time.Duration `conf:"env:SCHEDULER_INTERVAL,default:15s"`
+
+	// Args holds the subcommand, e.g. `migrate status`. Without one the
+	// server starts.
+	Args conf.Args
 }
 
-func NewDatabaseConfig() Database {
-	return Database{
-		DatabaseHost:     "localhost",
-		DatabasePort:     "5432",
-		DatabaseUser:     "postgres",
-		DatabasePassword: "Docker",
-		DatabaseName:     "maestro_db",
+// Validate rejects settings that are unsafe to run with.
+func (e *Env) Validate() error {
+	if e.Environment != ENV_DEVELOPMENT && e.Environment != ENV_PRODUCTION {
+		return fmt.Errorf("MAESTRO_ENV must be %q or %q", ENV_DEVELOPMENT, ENV_PRODUCTION)
+	}
 
-		RedisHost:     "localhost",
-		RedisPort:     "6379",
-		RedisPassword: "",
+	if len(e.JwtKeys) > 0 && e.JwtActiveKey == "" {
+		return errors.New("JWT_ACTIVE_KEY is required when JWT_KEYS is set")
 	}
-}
 
-func (d *Database) UrlConnection() string {
-	return fmt.Sprintf("postgresql://%s:%s@%s:%s/%s?sslmode=disable", d.DatabaseUser, d.DatabasePassword, d.DatabaseHost, d.DatabasePort, d.DatabaseName)
-}
+	if _, _, err := net.SplitHostPort(e.ListenAddress); err != nil {
+		return fmt.Errorf("invalid LISTEN_ADDRESS: %v", err)
+	}
 
-func (d *Database) RedisUrlConnection() string {
-	return fmt.Sprintf("%s:%s", d.RedisHost, d.RedisPort)
+	for _, proxy := range e.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				return fmt.Errorf("invalid TRUSTED_PROXIES entry %q", proxy)
+			}
+		}
+	}
+
+	if (e.TLSCertFile == "") != (e.TLSKeyFile == "") {
+		return errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+
+	if e.TLSSelfSigned && e.TLSCertFile == "" {
+		return errors.New("TLS_SELF_SIGNED requires TLS_CERT_FILE and TLS_KEY_FILE")
+	}
+
+	if err := e.Database.Validate(); err != nil {
+		return err
+	}
+
+	if err := e.Redis.Validate(); err != nil {
+		return err
+	}
+
+	if err := e.Tracing.Validate(); err != nil {
+		return err
+	}
+
+	if e.Environment == ENV_PRODUCTION && len(e.JwtKeys) == 0 {
+		if e.MaestroSecretKey == defaultSecretKey {
+			return errors.New("refusing to start in production with the default MAESTRO_SECRET_KEY")
+		}
+		if len(e.MaestroSecretKey) < 32 {
+			return errors.New("MAESTRO_SECRET_KEY must be at least 32 characters in production")
+		}
+	}
+
+	return nil
 }
 
-type Env struct {
-	WireguardEndpoint string `conf:"env:WIREGUARD_ENDPOINT"`
+// JWTKeySet builds the access token keys. Without JWT_KEYS tokens are signed
+// with MAESTRO_SECRET_KEY.
+func (e *Env) JWTKeySet() (*utils.JWTKeySet, error) {
+	if len(e.JwtKeys) == 0 {
+		return utils.NewJWTKeySet(utils.HMACKeyId, utils.NewHMACKey(utils.HMACKeyId, e.MaestroSecretKey))
+	}
 
-	MaestroUsername string `conf:"env:MAESTRO_USERNAME,default:maestro"`
-	MaestroPassword string `conf:"env:MAESTRO_PASSWORD,default:root"`
+	keys := []utils.JWTKey{}
+	for id, path := range e.JwtKeys {
+		key, err := utils.LoadJWTKey(id, path)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
 
-	MaestroSecretKey string `conf:"env:MAESTRO_SECRET_KEY,default:maestro_key_dev"`
+	return utils.NewJWTKeySet(e.JwtActiveKey, keys...)
 }
 
 func (e *Env) DefaultUser() dtos.CreateUserDTO {
@@ -55,3 +193,21 @@ func (e *Env) DefaultUser() dtos.CreateUserDTO {
 		Password: e.MaestroPassword,
 	}
 }
+
+func (e *Env) LoginThrottlePolicy() dtos.LoginThrottlePolicy {
+	return dtos.LoginThrottlePolicy{
+		MaxAttemptsPerUser: e.LoginMaxAttemptsPerUser,
+		MaxAttemptsPerIp:   e.LoginMaxAttemptsPerIp,
+		Window:             e.LoginAttemptWindow,
+		LockoutBase:        e.LoginLockoutBase,
+		LockoutMax:         e.LoginLockoutMax,
+	}
+}
+
+func (e *Env) TLSEnabled() bool {
+	return e.TLSCertFile != ""
+}
+
+func (e *Env) OidcEnabled() bool {
+	return e.OidcIssuerURL != ""
+}
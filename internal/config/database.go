@@ -0,0 +1,142 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"time"
+)
+
+var databaseSSLModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+type Database struct {
+	Host     string `conf:"env:DATABASE_HOST,default:localhost"`
+	Port     string `conf:"env:DATABASE_PORT,default:5432"`
+	User     string `conf:"env:DATABASE_USER,default:postgres"`
+	Password string `conf:"env:DATABASE_PASSWORD,default:Docker,mask"`
+	Name     string `conf:"env:DATABASE_NAME,default:maestro_db"`
+
+	// SSLMode is one of libpq's sslmode values. The certificate paths are
+	// only needed for verify-ca / verify-full and client certificates.
+	SSLMode     string `conf:"env:DATABASE_SSL_MODE,default:disable"`
+	SSLRootCert string `conf:"env:DATABASE_SSL_ROOT_CERT"`
+	SSLCert     string `conf:"env:DATABASE_SSL_CERT"`
+	SSLKey      string `conf:"env:DATABASE_SSL_KEY"`
+
+	MaxConns        int32         `conf:"env:DATABASE_MAX_CONNS,default:10"`
+	MinConns        int32         `conf:"env:DATABASE_MIN_CONNS,default:2"`
+	MaxConnLifetime time.Duration `conf:"env:DATABASE_MAX_CONN_LIFETIME,default:1h"`
+	MaxConnIdleTime time.Duration `conf:"env:DATABASE_MAX_CONN_IDLE_TIME,default:30m"`
+
+	// AutoMigrate applies pending migrations on startup. Turn it off to run
+	// `maestro-server migrate up` as a separate deployment step.
+	AutoMigrate bool `conf:"env:DATABASE_AUTO_MIGRATE,default:true"`
+}
+
+func (d *Database) Validate() error {
+	if !slices.Contains(databaseSSLModes, d.SSLMode) {
+		return fmt.Errorf("DATABASE_SSL_MODE must be one of %v", databaseSSLModes)
+	}
+
+	if (d.SSLCert == "") != (d.SSLKey == "") {
+		return errors.New("DATABASE_SSL_CERT and DATABASE_SSL_KEY must be set together")
+	}
+
+	if d.MaxConns < 1 || d.MinConns < 0 || d.MinConns > d.MaxConns {
+		return errors.New("database pool sizes must satisfy 0 <= DATABASE_MIN_CONNS <= DATABASE_MAX_CONNS and DATABASE_MAX_CONNS >= 1")
+	}
+
+	return nil
+}
+
+// UrlConnection returns the connection string for the pgx pool, pool
+// settings included.
+func (d *Database) UrlConnection() string {
+	query := url.Values{}
+	query.Set("sslmode", d.SSLMode)
+	if d.SSLRootCert != "" {
+		query.Set("sslrootcert", d.SSLRootCert)
+	}
+	if d.SSLCert != "" {
+		query.Set("sslcert", d.SSLCert)
+		query.Set("sslkey", d.SSLKey)
+	}
+	query.Set("pool_max_conns", fmt.Sprint(d.MaxConns))
+	query.Set("pool_min_conns", fmt.Sprint(d.MinConns))
+	query.Set("pool_max_conn_lifetime", d.MaxConnLifetime.String())
+	query.Set("pool_max_conn_idle_time", d.MaxConnIdleTime.String())
+
+	connection := url.URL{
+		Scheme:   "postgresql",
+		User:     url.UserPassword(d.User, d.Password),
+		Host:     net.JoinHostPort(d.Host, d.Port),
+		Path:     "/" + d.Name,
+		RawQuery: query.Encode(),
+	}
+
+	return connection.String()
+}
+
+type Redis struct {
+	Host     string `conf:"env:REDIS_HOST,default:localhost"`
+	Port     string `conf:"env:REDIS_PORT,default:6379"`
+	Username string `conf:"env:REDIS_USERNAME"`
+	Password string `conf:"env:REDIS_PASSWORD,mask"`
+	DB       int    `conf:"env:REDIS_DB,default:0"`
+	PoolSize int    `conf:"env:REDIS_POOL_SIZE,default:10"`
+
+	TLS       bool   `conf:"env:REDIS_TLS,default:false"`
+	TLSCACert string `conf:"env:REDIS_TLS_CA_CERT"`
+}
+
+func (r *Redis) Validate() error {
+	if r.DB < 0 || r.DB > 15 {
+		return errors.New("REDIS_DB must be between 0 and 15")
+	}
+
+	if r.PoolSize < 1 {
+		return errors.New("REDIS_POOL_SIZE must be at least 1")
+	}
+
+	if r.TLSCACert != "" && !r.TLS {
+		return errors.New("REDIS_TLS_CA_CERT requires REDIS_TLS=true")
+	}
+
+	return nil
+}
+
+func (r *Redis) Addr() string {
+	return net.JoinHostPort(r.Host, r.Port)
+}
+
+// TLSConfig returns nil when TLS is off. Without a CA certificate the
+// system roots are trusted.
+func (r *Redis) TLSConfig() (*tls.Config, error) {
+	if !r.TLS {
+		return nil, nil
+	}
+
+	config := &tls.Config{
+		ServerName: r.Host,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if r.TLSCACert != "" {
+		pem, err := os.ReadFile(r.TLSCACert)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read REDIS_TLS_CA_CERT: %v", err)
+		}
+
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, errors.New("REDIS_TLS_CA_CERT holds no PEM certificates")
+		}
+	}
+
+	return config, nil
+}
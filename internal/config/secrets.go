@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// LoadSecretFiles resolves the _FILE convention for every masked setting of
+// cfg: when FOO_FILE is set, FOO is set to the file's contents. It must run
+// before conf.Parse.
+func LoadSecretFiles(cfg any) error {
+	for _, name := range maskedEnvNames(reflect.TypeOf(cfg)) {
+		path, ok := os.LookupEnv(name + "_FILE")
+		if !ok {
+			continue
+		}
+
+		if _, ok := os.LookupEnv(name); ok {
+			return fmt.Errorf("%s and %s_FILE are both set", name, name)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("unable to read %s_FILE: %v", name, err)
+		}
+
+		// files written by editors and secret stores often end in a newline
+		if err := os.Setenv(name, strings.TrimRight(string(content), "\r\n")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func maskedEnvNames(t reflect.Type) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	names := []string{}
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if field.Type.Kind() == reflect.Struct && field.Type.PkgPath() == t.PkgPath() {
+			names = append(names, maskedEnvNames(field.Type)...)
+			continue
+		}
+
+		var env string
+		masked := false
+		for _, option := range strings.Split(field.Tag.Get("conf"), ",") {
+			switch {
+			case option == "mask":
+				masked = true
+			case strings.HasPrefix(option, "env:"):
+				env = strings.TrimPrefix(option, "env:")
+			}
+		}
+
+		if masked && env != "" {
+			names = append(names, env)
+		}
+	}
+
+	return names
+}
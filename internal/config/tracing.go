@@ -0,0 +1,30 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+)
+
+// Tracing exports OpenTelemetry spans with EXPORTER "otlp", over HTTP to
+// OTEL_EXPORTER_OTLP_ENDPOINT (e.g. http://collector:4318), or "stdout".
+// Incoming W3C trace context is forwarded to nodes even when it is "none".
+type Tracing struct {
+	Exporter    string  `conf:"env:TRACING_EXPORTER,default:none"`
+	Endpoint    string  `conf:"env:OTEL_EXPORTER_OTLP_ENDPOINT"`
+	ServiceName string  `conf:"env:OTEL_SERVICE_NAME,default:maestro-server"`
+	SampleRatio float64 `conf:"env:TRACING_SAMPLE_RATIO,default:1"`
+}
+
+func (t *Tracing) Validate() error {
+	if !dtos.TracingExporter(t.Exporter).Valid() {
+		return fmt.Errorf("TRACING_EXPORTER must be %q, %q or %q", dtos.TRACING_NONE, dtos.TRACING_OTLP, dtos.TRACING_STDOUT)
+	}
+
+	if t.SampleRatio < 0 || t.SampleRatio > 1 {
+		return errors.New("TRACING_SAMPLE_RATIO must be between 0 and 1")
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package dtos
+
+import "time"
+
+// ApiKeyPrefix marks a bearer token as an API key rather than a JWT.
+const ApiKeyPrefix = "mk_"
+
+type ApiKey struct {
+	Id         string       `json:"id"`
+	UserId     string       `json:"userId"`
+	Name       string       `json:"name"`
+	Prefix     string       `json:"prefix"`
+	Scopes     []Permission `json:"scopes"`
+	ExpiresAt  *time.Time   `json:"expiresAt"`
+	LastUsedAt *time.Time   `json:"lastUsedAt"`
+	CreatedAt  time.Time    `json:"createdAt"`
+}
+
+// CreatedApiKey is only returned once, when the key is created. Key is never
+// stored and cannot be retrieved again.
+type CreatedApiKey struct {
+	ApiKey
+	Key string `json:"key"`
+}
+
+// CreateApiKeyDTO creates a key for UserId. An empty Scopes grants every
+// permission of the owner's role; otherwise the key is limited to Scopes.
+type CreateApiKeyDTO struct {
+	UserId    string       `json:"-"`
+	Role      Role         `json:"-"`
+	Name      string       `json:"name" binding:"required,min=1,max=100"`
+	Scopes    []Permission `json:"scopes" binding:"omitempty,dive,permission"`
+	ExpiresAt *time.Time   `json:"expiresAt"`
+}
+
+type DeleteApiKeyDTO struct {
+	Id     string `json:"id"`
+	UserId string `json:"userId"`
+}
+
+// ApiKeyPrincipal is the caller behind a valid API key.
+type ApiKeyPrincipal struct {
+	ApiKeyId string       `json:"apiKeyId"`
+	UserId   string       `json:"userId"`
+	Role     Role         `json:"role"`
+	Scopes   []Permission `json:"scopes"`
+}
@@ -0,0 +1,43 @@
+package dtos
+
+import "time"
+
+type TypeAuditOutcome string
+
+const (
+	AUDIT_SUCCESS TypeAuditOutcome = "SUCCESS"
+	AUDIT_DENIED  TypeAuditOutcome = "DENIED"
+	AUDIT_FAILURE TypeAuditOutcome = "FAILURE"
+)
+
+type AuditEntry struct {
+	Id         string           `json:"id"`
+	ActorId    string           `json:"actorId"`
+	ApiKeyId   string           `json:"apiKeyId"`
+	Action     string           `json:"action"`
+	NodeId     string           `json:"nodeId"`
+	Target     string           `json:"target"`
+	IpAddress  string           `json:"ipAddress"`
+	RequestId  string           `json:"requestId"`
+	Outcome    TypeAuditOutcome `json:"outcome"`
+	StatusCode int              `json:"statusCode"`
+	CreatedAt  time.Time        `json:"createdAt"`
+}
+
+// FindAuditDTO filters the audit log. Entries come newest first; Cursor is
+// the NextCursor of the previous page.
+type FindAuditDTO struct {
+	ActorId string     `form:"actor"`
+	NodeId  string     `form:"node"`
+	Action  string     `form:"action"`
+	From    *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To      *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Cursor  string     `form:"cursor"`
+	Limit   int        `form:"limit" binding:"omitempty,min=1,max=500"`
+	Format  string     `form:"format" binding:"omitempty,oneof=json csv"`
+}
+
+type AuditPage struct {
+	Entries    []AuditEntry `json:"entries"`
+	NextCursor string       `json:"nextCursor,omitempty"`
+}
@@ -3,4 +3,7 @@ package dtos
 type AuthUserDTO struct {
 	Username string `json:"username" binding:"required"`
 	Password string `json:"password" binding:"required"`
+
+	UserAgent string `json:"-"`
+	IpAddress string `json:"-"`
 }
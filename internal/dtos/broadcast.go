@@ -0,0 +1,62 @@
+package dtos
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+type NodeSelector struct {
+	Ids             []string          `json:"ids"`
+	OperatingSystem OperatingSystem   `json:"operatingSystem" binding:"omitempty,operatingsystem"`
+	Status          TypeNodeStatus    `json:"status" binding:"omitempty,oneof=UP DOWN"`
+	Labels          map[string]string `json:"labels"`
+}
+
+// Matches reports whether the node satisfies every criterion set on the
+// selector. An empty selector matches all nodes.
+func (s NodeSelector) Matches(node Node) bool {
+	if len(s.Ids) > 0 && !slices.Contains(s.Ids, node.Id) {
+		return false
+	}
+
+	if s.OperatingSystem != "" && s.OperatingSystem != node.OperatingSystem {
+		return false
+	}
+
+	if s.Status != "" && s.Status != node.Status {
+		return false
+	}
+
+	for key, value := range s.Labels {
+		if node.Labels[key] != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+type BroadcastDTO struct {
+	Method      string            `json:"method" binding:"required,oneof=GET POST PUT PATCH DELETE HEAD OPTIONS"`
+	Path        string            `json:"path" binding:"required,startswith=/"`
+	Headers     map[string]string `json:"headers"`
+	Body        json.RawMessage   `json:"body"`
+	Selector    NodeSelector      `json:"selector"`
+	Concurrency int               `json:"concurrency" binding:"omitempty,min=1,max=50"`
+	TimeoutMs   int               `json:"timeoutMs" binding:"omitempty,min=1,max=300000"`
+
+	// Scope limits the broadcast to the nodes the caller may see.
+	Scope NodeScope `json:"-"`
+
+	// OnResult, when set, is called once per node as soon as it answers.
+	// Calls are serialized, so it is safe to write to a single stream.
+	OnResult func(BroadcastResult) `json:"-"`
+}
+
+type BroadcastResult struct {
+	NodeId     string          `json:"nodeId"`
+	StatusCode int             `json:"statusCode"`
+	Body       json.RawMessage `json:"body"`
+	LatencyMs  int64           `json:"latencyMs"`
+	Error      string          `json:"error,omitempty"`
+}
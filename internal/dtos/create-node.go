@@ -3,8 +3,10 @@ package dtos
 import "github.com/go-playground/validator/v10"
 
 type CreateNodeDTO struct {
+	Scope NodeScope `json:"-"`
 	Name string `json:"name" binding:"required"`
 	OperatingSystem OperatingSystem `json:"operatingSystem" binding:"required,operatingsystem"` 
+	Labels map[string]string `json:"labels"`
 }
 
 func ValidateOperatingSystem(fl validator.FieldLevel) bool {
@@ -18,4 +20,4 @@ func ValidateOperatingSystem(fl validator.FieldLevel) bool {
 	}
 
 	return false
-}
\ No newline at end of file
+}
@@ -1,6 +1,7 @@
 package dtos
 
 type CreateUserDTO struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username string `json:"username" binding:"required,min=3,max=255"`
+	Password string `json:"password" binding:"required,min=8,max=72"`
+	Role     Role   `json:"role" binding:"omitempty,role"`
 }
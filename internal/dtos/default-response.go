@@ -1,23 +1,33 @@
 package dtos
 
-import (
-	"time"
-
-	"github.com/oklog/ulid/v2"
-)
+import "time"
 
 type defaultResponse struct {
-	RequestId string `json:"requestId"`
-	Message string `json:"message"`
-	Data any `json:"data"`
-	Timestamp time.Time `json:"timestamp"`
+	RequestId string       `json:"requestId"`
+	Code      ErrorCode    `json:"code,omitempty"`
+	Message   string       `json:"message"`
+	Errors    []FieldError `json:"errors,omitempty"`
+	Data      any          `json:"data"`
+	Timestamp time.Time    `json:"timestamp"`
 }
 
-func NewDefaultResponse(message string, data any) defaultResponse {
+// NewDefaultResponse wraps data; requestId is the id the RequestId middleware
+// assigned, so clients can quote it when reporting a problem.
+func NewDefaultResponse(requestId string, message string, data any) defaultResponse {
 	return defaultResponse{
-		RequestId: ulid.Make().String(),
-		Message: message,
-		Data: data,
+		RequestId: requestId,
+		Message:   message,
+		Data:      data,
 		Timestamp: time.Now(),
 	}
-}
\ No newline at end of file
+}
+
+// NewErrorResponse describes a failed request; fields lists the invalid
+// input when code is VALIDATION_FAILED.
+func NewErrorResponse(requestId string, code ErrorCode, message string, fields []FieldError) defaultResponse {
+	response := NewDefaultResponse(requestId, message, nil)
+	response.Code = code
+	response.Errors = fields
+
+	return response
+}
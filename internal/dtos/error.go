@@ -0,0 +1,47 @@
+package dtos
+
+import (
+	"reflect"
+	"strings"
+)
+
+// ErrorCode tells API clients why a request failed without parsing the
+// message.
+type ErrorCode string
+
+const (
+	BAD_REQUEST          ErrorCode = "BAD_REQUEST"
+	VALIDATION_FAILED    ErrorCode = "VALIDATION_FAILED"
+	UNAUTHORIZED         ErrorCode = "UNAUTHORIZED"
+	FORBIDDEN            ErrorCode = "FORBIDDEN"
+	NOT_FOUND            ErrorCode = "NOT_FOUND"
+	CONFLICT             ErrorCode = "CONFLICT"
+	PAYLOAD_TOO_LARGE    ErrorCode = "PAYLOAD_TOO_LARGE"
+	UNPROCESSABLE        ErrorCode = "UNPROCESSABLE"
+	TOO_MANY_REQUESTS    ErrorCode = "TOO_MANY_REQUESTS"
+	UPSTREAM_UNAVAILABLE ErrorCode = "UPSTREAM_UNAVAILABLE"
+	INTERNAL             ErrorCode = "INTERNAL"
+)
+
+// FieldError is one rejected input field, named as the client sent it.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// FieldName names struct fields in validation errors after their json, or
+// else form, tag.
+func FieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+
+	return field.Name
+}
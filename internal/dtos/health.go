@@ -0,0 +1,19 @@
+package dtos
+
+const (
+	CHECK_UP   = "up"
+	CHECK_DOWN = "down"
+)
+
+// DependencyCheck is the outcome of probing one dependency for /readyz.
+type DependencyCheck struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latencyMs"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Readiness is up only when every check is.
+type Readiness struct {
+	Status string                     `json:"status"`
+	Checks map[string]DependencyCheck `json:"checks"`
+}
@@ -0,0 +1,87 @@
+package dtos
+
+import "time"
+
+type TypeJobStatus = string
+
+const (
+	JOB_RUNNING   TypeJobStatus = "RUNNING"
+	JOB_SUCCEEDED TypeJobStatus = "SUCCEEDED"
+	JOB_FAILED    TypeJobStatus = "FAILED"
+	JOB_CANCELLED TypeJobStatus = "CANCELLED"
+	JOB_TIMED_OUT TypeJobStatus = "TIMED_OUT"
+
+	STDOUT = "stdout"
+	STDERR = "stderr"
+	EXIT   = "exit"
+
+	// MASKED_ENV_VALUE replaces the value of every environment variable
+	// stored or returned for a job or schedule; only the names are kept.
+	MASKED_ENV_VALUE = "********"
+)
+
+// MaskEnv returns env with every value replaced by MASKED_ENV_VALUE.
+// Environment variables often carry secrets, so their values are only ever
+// sent to the node agent.
+func MaskEnv(env map[string]string) map[string]string {
+	masked := make(map[string]string, len(env))
+	for name := range env {
+		masked[name] = MASKED_ENV_VALUE
+	}
+
+	return masked
+}
+
+type Job struct {
+	Id         string            `json:"id"`
+	NodeId     string            `json:"nodeId"`
+	UserId     string            `json:"userId"`
+	Command    string            `json:"command"`
+	Args       []string          `json:"args"`
+	Env        map[string]string `json:"env"`
+	WorkingDir string            `json:"workingDir"`
+	Status     TypeJobStatus     `json:"status"`
+	ExitCode   *int              `json:"exitCode"`
+	Stdout     string            `json:"stdout,omitempty"`
+	Stderr     string            `json:"stderr,omitempty"`
+	DurationMs *int64            `json:"durationMs"`
+	CreatedAt  time.Time         `json:"createdAt"`
+	FinishedAt *time.Time        `json:"finishedAt"`
+}
+
+type ExecDTO struct {
+	NodeId     string            `json:"-"`
+	UserId     string            `json:"-"`
+	Scope      NodeScope         `json:"-"`
+	Command    string            `json:"command" binding:"required"`
+	Args       []string          `json:"args"`
+	Env        map[string]string `json:"env"`
+	WorkingDir string            `json:"workingDir"`
+	TimeoutMs  int               `json:"timeoutMs" binding:"omitempty,min=1,max=3600000"`
+
+	// OnOutput, when set, receives every chunk of output as the node agent
+	// emits it. OnStart is called once the job record has been created.
+	OnStart  func(Job)       `json:"-"`
+	OnOutput func(JobOutput) `json:"-"`
+}
+
+// JobOutput is a single event of the node agent's /exec stream.
+type JobOutput struct {
+	Stream   string `json:"stream"`
+	Data     string `json:"data,omitempty"`
+	ExitCode *int   `json:"exitCode,omitempty"`
+}
+
+type FindJobsDTO struct {
+	Scope  NodeScope     `form:"-"`
+	NodeId string        `form:"nodeId"`
+	Status TypeJobStatus `form:"status" binding:"omitempty,oneof=RUNNING SUCCEEDED FAILED CANCELLED TIMED_OUT"`
+	Limit  int           `form:"limit" binding:"omitempty,min=1,max=500"`
+}
+
+// JobAccessDTO names a job the caller wants to read or cancel. Jobs on nodes
+// outside Scope are reported as missing.
+type JobAccessDTO struct {
+	Scope NodeScope
+	JobId string
+}
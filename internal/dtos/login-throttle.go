@@ -0,0 +1,15 @@
+package dtos
+
+import "time"
+
+// LoginThrottlePolicy bounds password guessing on POST /auth. Once a
+// username or an IP address reaches its attempt limit inside Window, it is
+// locked out for LockoutBase, doubling with every further failure up to
+// LockoutMax.
+type LoginThrottlePolicy struct {
+	MaxAttemptsPerUser int
+	MaxAttemptsPerIp   int
+	Window             time.Duration
+	LockoutBase        time.Duration
+	LockoutMax         time.Duration
+}
@@ -0,0 +1,16 @@
+package dtos
+
+// MigrationStatus describes the schema version of the database against the
+// migrations embedded in the binary. Dirty means a migration failed half
+// way; fix the schema by hand and force the version before migrating again.
+type MigrationStatus struct {
+	Version    uint
+	Dirty      bool
+	Migrations []Migration
+}
+
+type Migration struct {
+	Version uint
+	Name    string
+	Applied bool
+}
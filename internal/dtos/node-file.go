@@ -0,0 +1,61 @@
+package dtos
+
+import (
+	"io"
+	"time"
+)
+
+type FileEntry struct {
+	Name       string    `json:"name"`
+	Path       string    `json:"path"`
+	Size       int64     `json:"size"`
+	IsDir      bool      `json:"isDir"`
+	Mode       string    `json:"mode"`
+	ModifiedAt time.Time `json:"modifiedAt"`
+}
+
+type NodeFileDTO struct {
+	NodeId string    `json:"-"`
+	Scope  NodeScope `form:"-" json:"-"`
+	Path   string    `form:"path" json:"path" binding:"required"`
+}
+
+type UploadFileDTO struct {
+	NodeId string    `json:"nodeId"`
+	Scope  NodeScope `json:"-"`
+	Path   string    `json:"path"`
+	Body   io.Reader `json:"-"`
+
+	// Offset, Length and Total come from the Content-Range of a chunk. Total
+	// is zero when the whole file is sent in a single request.
+	Offset int64 `json:"offset"`
+	Length int64 `json:"length"`
+	Total  int64 `json:"total"`
+
+	// Sha256 is the hex digest the client expects for the complete file.
+	Sha256 string `json:"sha256"`
+}
+
+type FileTransfer struct {
+	Path     string `json:"path"`
+	Received int64  `json:"received"`
+	Total    int64  `json:"total"`
+	Complete bool   `json:"complete"`
+	Sha256   string `json:"sha256,omitempty"`
+}
+
+type DownloadFileDTO struct {
+	NodeId string    `json:"nodeId"`
+	Scope  NodeScope `json:"-"`
+	Path   string    `json:"path"`
+	Range  string    `json:"range"`
+}
+
+type FileStream struct {
+	Body          io.ReadCloser `json:"-"`
+	StatusCode    int           `json:"statusCode"`
+	ContentLength int64         `json:"contentLength"`
+	ContentRange  string        `json:"contentRange"`
+	ContentType   string        `json:"contentType"`
+	Sha256        string        `json:"sha256"`
+}
@@ -0,0 +1,53 @@
+package dtos
+
+import "time"
+
+type NodeGroup struct {
+	Id          string     `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	NodeIds     []string   `json:"nodeIds"`
+	UserIds     []string   `json:"userIds"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   *time.Time `json:"updatedAt"`
+}
+
+// NodeGroupDTO is used to create and update a group. NodeIds and UserIds
+// replace the current members and grants.
+type NodeGroupDTO struct {
+	Id          string   `json:"-"`
+	Name        string   `json:"name" binding:"required,min=1,max=100"`
+	Description string   `json:"description"`
+	NodeIds     []string `json:"nodeIds"`
+	UserIds     []string `json:"userIds"`
+}
+
+// NodeScope describes the caller a use case acts for: which nodes they may
+// see and, through Role and the Scopes of an API key, what they may do. All
+// is set for roles that see every node and for internal callers; otherwise
+// only nodes in the groups granted to UserId are visible.
+type NodeScope struct {
+	All    bool
+	UserId string
+	Role   Role
+	Scopes []Permission
+}
+
+func NewNodeScope(userId string, role Role, scopes []Permission) NodeScope {
+	return NodeScope{
+		All:    role.Can(PERM_NODES_ALL),
+		UserId: userId,
+		Role:   role,
+		Scopes: scopes,
+	}
+}
+
+// Can reports whether the caller holds the permission.
+func (s NodeScope) Can(permission Permission) bool {
+	return s.Role.Allows(permission, s.Scopes)
+}
+
+type NodeAccessDTO struct {
+	Scope  NodeScope
+	NodeId string
+}
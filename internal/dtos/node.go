@@ -36,10 +36,11 @@ type VpnConfig struct {
 }
 
 type Node struct {
-	Id              string          `json:"id"`
-	Name            string          `json:"name"`
-	VpnAddress      string          `json:"vpnAddress"`
-	OperatingSystem OperatingSystem `json:"operatingSystem"`
-	Status          TypeNodeStatus  `json:"status"`
-	VpnConfig       string          `json:"vpnConfig"`
+	Id              string            `json:"id"`
+	Name            string            `json:"name"`
+	VpnAddress      string            `json:"vpnAddress"`
+	OperatingSystem OperatingSystem   `json:"operatingSystem"`
+	Status          TypeNodeStatus    `json:"status"`
+	Labels          map[string]string `json:"labels"`
+	VpnConfig       string            `json:"vpnConfig"`
 }
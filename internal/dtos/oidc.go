@@ -0,0 +1,28 @@
+package dtos
+
+type ExternalIdentity struct {
+	Issuer   string   `json:"issuer"`
+	Subject  string   `json:"subject"`
+	Username string   `json:"username"`
+	Email    string   `json:"email"`
+	Groups   []string `json:"groups"`
+}
+
+type OidcLoginState struct {
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"codeVerifier"`
+}
+
+// OidcLogin is a started login: the browser is sent to AuthURL and keeps
+// State in a cookie so the callback can tell it started the login.
+type OidcLogin struct {
+	State   string
+	AuthURL string
+}
+
+type OidcCallbackDTO struct {
+	State     string `form:"state" binding:"required"`
+	Code      string `form:"code" binding:"required"`
+	UserAgent string `form:"-"`
+	IpAddress string `form:"-"`
+}
@@ -0,0 +1,91 @@
+package dtos
+
+import (
+	"slices"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type Role string
+
+type Permission string
+
+const (
+	ADMIN    Role = "ADMIN"
+	OPERATOR Role = "OPERATOR"
+	VIEWER   Role = "VIEWER"
+
+	PERM_NODES_READ       Permission = "nodes:read"
+	PERM_NODES_ALL        Permission = "nodes:all"
+	PERM_NODES_WRITE      Permission = "nodes:write"
+	PERM_NODES_VPN_CONFIG Permission = "nodes:vpn-config"
+	PERM_NODES_PROXY      Permission = "nodes:proxy"
+	PERM_NODES_EXEC       Permission = "nodes:exec"
+	PERM_NODES_SHELL      Permission = "nodes:shell"
+	PERM_FILES_READ       Permission = "files:read"
+	PERM_FILES_WRITE      Permission = "files:write"
+	PERM_JOBS_READ        Permission = "jobs:read"
+	PERM_JOBS_CANCEL      Permission = "jobs:cancel"
+	PERM_SCHEDULES_READ   Permission = "schedules:read"
+	PERM_SCHEDULES_WRITE  Permission = "schedules:write"
+	PERM_SHELL_SESSIONS   Permission = "shell-sessions:manage"
+	PERM_USERS_MANAGE     Permission = "users:manage"
+	PERM_NODE_GROUPS      Permission = "node-groups:manage"
+	PERM_AUDIT_READ       Permission = "audit:read"
+)
+
+var viewerPermissions = []Permission{
+	PERM_NODES_READ,
+	PERM_JOBS_READ,
+	PERM_SCHEDULES_READ,
+}
+
+var operatorPermissions = append(slices.Clone(viewerPermissions),
+	PERM_NODES_PROXY,
+	PERM_NODES_EXEC,
+	PERM_NODES_SHELL,
+	PERM_FILES_READ,
+	PERM_FILES_WRITE,
+	PERM_JOBS_CANCEL,
+	PERM_SCHEDULES_WRITE,
+)
+
+var adminPermissions = append(slices.Clone(operatorPermissions),
+	PERM_NODES_ALL,
+	PERM_NODES_WRITE,
+	PERM_NODES_VPN_CONFIG,
+	PERM_NODE_GROUPS,
+	PERM_SHELL_SESSIONS,
+	PERM_USERS_MANAGE,
+	PERM_AUDIT_READ,
+)
+
+var rolePermissions = map[Role][]Permission{
+	ADMIN:    adminPermissions,
+	OPERATOR: operatorPermissions,
+	VIEWER:   viewerPermissions,
+}
+
+func (r Role) Can(permission Permission) bool {
+	return slices.Contains(rolePermissions[r], permission)
+}
+
+// Allows reports whether a caller with this role and, for API keys, these
+// scopes holds the permission. Nil scopes mean the caller is not restricted
+// beyond its role.
+func (r Role) Allows(permission Permission, scopes []Permission) bool {
+	return r.Can(permission) && (scopes == nil || slices.Contains(scopes, permission))
+}
+
+func (r Role) Valid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+func ValidateRole(fl validator.FieldLevel) bool {
+	return Role(fl.Field().String()).Valid()
+}
+
+func ValidatePermission(fl validator.FieldLevel) bool {
+	return slices.Contains(adminPermissions, Permission(fl.Field().String()))
+}
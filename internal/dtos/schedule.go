@@ -0,0 +1,118 @@
+package dtos
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/robfig/cron/v3"
+)
+
+type ScheduleActionType = string
+
+type TypeScheduleRunStatus = string
+
+const (
+	SCHEDULE_PROXY ScheduleActionType = "PROXY"
+	SCHEDULE_EXEC  ScheduleActionType = "EXEC"
+
+	RUN_RUNNING   TypeScheduleRunStatus = "RUNNING"
+	RUN_SUCCEEDED TypeScheduleRunStatus = "SUCCEEDED"
+	RUN_FAILED    TypeScheduleRunStatus = "FAILED"
+	RUN_SKIPPED   TypeScheduleRunStatus = "SKIPPED"
+)
+
+// ScheduleAction holds the request sent to every selected node. Method, Path,
+// Headers and Body describe a PROXY action; Command, Args, Env, WorkingDir
+// and TimeoutMs describe an EXEC action.
+type ScheduleAction struct {
+	Method  string            `json:"method,omitempty"`
+	Path    string            `json:"path,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+
+	Command    string            `json:"command,omitempty"`
+	Args       []string          `json:"args,omitempty"`
+	Env        map[string]string `json:"env,omitempty"`
+	WorkingDir string            `json:"workingDir,omitempty"`
+	TimeoutMs  int               `json:"timeoutMs,omitempty"`
+}
+
+type Schedule struct {
+	Id             string             `json:"id"`
+	Name           string             `json:"name"`
+	CronExpression string             `json:"cronExpression"`
+	Selector       NodeSelector       `json:"selector"`
+	ActionType     ScheduleActionType `json:"actionType"`
+	Action         ScheduleAction     `json:"action"`
+	MaxRetries     int                `json:"maxRetries"`
+	RetryDelayMs   int                `json:"retryDelayMs"`
+	Enabled        bool               `json:"enabled"`
+	CreatedBy      string             `json:"createdBy"`
+	NextRunAt      *time.Time         `json:"nextRunAt"`
+	LastRunAt      *time.Time         `json:"lastRunAt"`
+	CreatedAt      time.Time          `json:"createdAt"`
+}
+
+// Masked returns the schedule with the values of its EXEC environment
+// masked, for responses.
+func (s Schedule) Masked() Schedule {
+	if s.Action.Env != nil {
+		s.Action.Env = MaskEnv(s.Action.Env)
+	}
+
+	return s
+}
+
+type ScheduleDTO struct {
+	Id             string             `json:"-"`
+	CreatedBy      string             `json:"-"`
+	Scope          NodeScope          `json:"-"`
+	Name           string             `json:"name" binding:"required"`
+	CronExpression string             `json:"cronExpression" binding:"required,cron"`
+	Selector       NodeSelector       `json:"selector"`
+	ActionType     ScheduleActionType `json:"actionType" binding:"required,oneof=PROXY EXEC"`
+	Action         ScheduleAction     `json:"action"`
+	MaxRetries     int                `json:"maxRetries" binding:"omitempty,min=0,max=10"`
+	RetryDelayMs   int                `json:"retryDelayMs" binding:"omitempty,min=0,max=3600000"`
+	Enabled        *bool              `json:"enabled"`
+}
+
+type ScheduleRunResult struct {
+	NodeId     string `json:"nodeId"`
+	Attempts   int    `json:"attempts"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	JobId      string `json:"jobId,omitempty"`
+	ExitCode   *int   `json:"exitCode,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+type ScheduleRun struct {
+	Id           string                       `json:"id"`
+	ScheduleId   string                       `json:"scheduleId"`
+	Status       TypeScheduleRunStatus        `json:"status"`
+	Attempts     int                          `json:"attempts"`
+	Results      map[string]ScheduleRunResult `json:"results"`
+	Error        string                       `json:"error,omitempty"`
+	ScheduledFor time.Time                    `json:"scheduledFor"`
+	StartedAt    time.Time                    `json:"startedAt"`
+	FinishedAt   *time.Time                   `json:"finishedAt"`
+}
+
+type FindScheduleRunsDTO struct {
+	Scope      NodeScope `form:"-"`
+	ScheduleId string    `form:"-"`
+	Limit      int       `form:"limit" binding:"omitempty,min=1,max=500"`
+}
+
+// ScheduleAccessDTO names a schedule the caller wants to read or change.
+// Schedules Scope does not reach are reported as missing.
+type ScheduleAccessDTO struct {
+	Scope      NodeScope
+	ScheduleId string
+}
+
+func ValidateCronExpression(fl validator.FieldLevel) bool {
+	_, err := cron.ParseStandard(fl.Field().String())
+	return err == nil
+}
@@ -0,0 +1,39 @@
+package dtos
+
+import "time"
+
+type Session struct {
+	Id         string    `json:"id"`
+	UserId     string    `json:"userId"`
+	UserAgent  string    `json:"userAgent"`
+	IpAddress  string    `json:"ipAddress"`
+	Current    bool      `json:"current"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+type TokenPair struct {
+	AccessToken          string    `json:"accessToken"`
+	AccessTokenExpiresAt time.Time `json:"accessTokenExpiresAt"`
+	RefreshToken         string    `json:"refreshToken"`
+	SessionId            string    `json:"sessionId"`
+}
+
+type CreateSessionDTO struct {
+	UserId    string `json:"userId"`
+	Role      Role   `json:"role"`
+	UserAgent string `json:"userAgent"`
+	IpAddress string `json:"ipAddress"`
+}
+
+type RefreshSessionDTO struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+	UserAgent    string `json:"-"`
+	IpAddress    string `json:"-"`
+}
+
+type RevokeSessionDTO struct {
+	SessionId string `json:"sessionId"`
+	UserId    string `json:"userId"`
+}
@@ -0,0 +1,54 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	SHELL_INPUT  = "input"
+	SHELL_OUTPUT = "output"
+	SHELL_RESIZE = "resize"
+	SHELL_EXIT   = "exit"
+	SHELL_CLOSED = "closed"
+)
+
+type ShellSession struct {
+	Id            string     `json:"id"`
+	NodeId        string     `json:"nodeId"`
+	UserId        string     `json:"userId"`
+	Shell         string     `json:"shell"`
+	RecordingPath string     `json:"-"`
+	CloseReason   string     `json:"closeReason"`
+	StartedAt     time.Time  `json:"startedAt"`
+	EndedAt       *time.Time `json:"endedAt"`
+}
+
+// ShellMessage is the JSON frame exchanged with both the browser and the node
+// agent. Browsers send input and resize frames, agents answer with output and
+// exit frames, and the server emits closed when it ends the session.
+type ShellMessage struct {
+	Type   string `json:"type"`
+	Data   string `json:"data,omitempty"`
+	Cols   int    `json:"cols,omitempty"`
+	Rows   int    `json:"rows,omitempty"`
+	Code   *int   `json:"code,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type OpenShellDTO struct {
+	Node   Node            `json:"-"`
+	UserId string          `json:"-"`
+	Scope  NodeScope       `form:"-" json:"-"`
+	Conn   *websocket.Conn `json:"-"`
+	Shell  string          `form:"shell" json:"shell"`
+	Cols   int             `form:"cols" json:"cols" binding:"omitempty,min=1,max=1000"`
+	Rows   int             `form:"rows" json:"rows" binding:"omitempty,min=1,max=1000"`
+}
+
+type FindShellSessionsDTO struct {
+	NodeId string `form:"nodeId"`
+	Active bool   `form:"active"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=500"`
+}
@@ -0,0 +1,12 @@
+package dtos
+
+import "time"
+
+type TokenClaims struct {
+	Jti       string    `json:"jti"`
+	SessionId string    `json:"sessionId"`
+	UserId    string    `json:"userId"`
+	Role      Role      `json:"role"`
+	IssuedAt  time.Time `json:"issuedAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
@@ -0,0 +1,18 @@
+package dtos
+
+type TracingExporter string
+
+const (
+	TRACING_NONE   TracingExporter = "none"
+	TRACING_OTLP   TracingExporter = "otlp"
+	TRACING_STDOUT TracingExporter = "stdout"
+)
+
+func (e TracingExporter) Valid() bool {
+	switch e {
+	case TRACING_NONE, TRACING_OTLP, TRACING_STDOUT:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,53 @@
+package dtos
+
+import "time"
+
+type AuthPolicy struct {
+	RequireTwoFactor bool `json:"requireTwoFactor"`
+}
+
+type TotpEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+	QRCode string `json:"qrCode"`
+}
+
+// AuthChallenge is returned by POST /auth instead of tokens when the user
+// must pass a second factor. When EnrollmentRequired is set the user has no
+// authenticator yet and must confirm the one in Enrollment.
+type AuthChallenge struct {
+	Token              string          `json:"token"`
+	ExpiresAt          time.Time       `json:"expiresAt"`
+	EnrollmentRequired bool            `json:"enrollmentRequired"`
+	Enrollment         *TotpEnrollment `json:"enrollment,omitempty"`
+}
+
+// AuthResult holds either the session tokens or a challenge. Recovery codes
+// are only set when the login also completed a required enrolment.
+type AuthResult struct {
+	*TokenPair
+	Challenge     *AuthChallenge `json:"challenge,omitempty"`
+	RecoveryCodes []string       `json:"recoveryCodes,omitempty"`
+}
+
+type TwoFactorChallengeState struct {
+	UserId             string    `json:"userId"`
+	Role               Role      `json:"role"`
+	EnrollmentRequired bool      `json:"enrollmentRequired"`
+	UserAgent          string    `json:"userAgent"`
+	IpAddress          string    `json:"ipAddress"`
+	ExpiresAt          time.Time `json:"expiresAt"`
+}
+
+type VerifyTwoFactorDTO struct {
+	ChallengeToken string `json:"challengeToken" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+	IpAddress      string `json:"-"`
+}
+
+// TwoFactorCodeDTO carries a TOTP code, or a recovery code where the action
+// accepts one, for an action on the caller's own second factor.
+type TwoFactorCodeDTO struct {
+	UserId string `json:"-"`
+	Code   string `json:"code" binding:"required"`
+}
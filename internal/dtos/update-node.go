@@ -1,7 +1,9 @@
 package dtos
 
 type UpdateNodeDTO struct {
-	Id              string          `json:"id"`
-	Name            string          `json:"name" binding:"required"`
-	OperatingSystem OperatingSystem `json:"operatingSystem" binding:"required,operatingsystem"`
+	Id              string            `json:"id"`
+	Scope           NodeScope         `json:"-"`
+	Name            string            `json:"name" binding:"required"`
+	OperatingSystem OperatingSystem   `json:"operatingSystem" binding:"required,operatingsystem"`
+	Labels          map[string]string `json:"labels"`
 }
@@ -0,0 +1,38 @@
+package dtos
+
+import "time"
+
+type User struct {
+	Id               string     `json:"id"`
+	Username         string     `json:"username"`
+	Role             Role       `json:"role"`
+	Disabled         bool       `json:"disabled"`
+	TwoFactorEnabled bool       `json:"twoFactorEnabled"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	UpdatedAt        *time.Time `json:"updatedAt"`
+	LastLoginAt      *time.Time `json:"lastLoginAt"`
+}
+
+type UpdateUserDTO struct {
+	Id       string `json:"-"`
+	ActorId  string `json:"-"`
+	Username string `json:"username" binding:"required,min=3,max=255"`
+	Role     Role   `json:"role" binding:"omitempty,role"`
+	Disabled bool   `json:"disabled"`
+}
+
+type DeleteUserDTO struct {
+	Id      string
+	ActorId string
+}
+
+type ChangePasswordDTO struct {
+	UserId          string `json:"-"`
+	CurrentPassword string `json:"currentPassword" binding:"required"`
+	NewPassword     string `json:"newPassword" binding:"required,min=8,max=72"`
+}
+
+type ResetPasswordDTO struct {
+	UserId      string `json:"-"`
+	NewPassword string `json:"newPassword" binding:"required,min=8,max=72"`
+}
@@ -0,0 +1,92 @@
+package errs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/go-playground/validator/v10"
+)
+
+// Binding converts a gin binding error into a validation error listing each
+// invalid field, or a bad request when the body could not be decoded.
+func Binding(err error) *Error {
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		fields := []dtos.FieldError{}
+		for _, fieldError := range validationErrors {
+			fields = append(fields, dtos.FieldError{
+				Field:   fieldPath(fieldError),
+				Rule:    fieldError.Tag(),
+				Message: fieldMessage(fieldError),
+			})
+		}
+
+		return Validation(fields...)
+	}
+
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) {
+		return Validation(dtos.FieldError{
+			Field:   typeError.Field,
+			Rule:    "type",
+			Message: fmt.Sprintf("must be a %s", typeError.Type.String()),
+		})
+	}
+
+	if errors.Is(err, io.EOF) {
+		return BadRequest("request body is empty")
+	}
+
+	return BadRequest("malformed request body")
+}
+
+// fieldPath drops the top-level struct name validator prefixes namespaces
+// with, e.g. "CreateNodeDTO.name" becomes "name".
+func fieldPath(fieldError validator.FieldError) string {
+	_, path, found := strings.Cut(fieldError.Namespace(), ".")
+	if !found {
+		return fieldError.Field()
+	}
+
+	return path
+}
+
+func fieldMessage(fieldError validator.FieldError) string {
+	switch fieldError.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		return "must be at least " + fieldError.Param() + lengthUnit(fieldError)
+	case "max":
+		return "must be at most " + fieldError.Param() + lengthUnit(fieldError)
+	case "oneof":
+		return "must be one of: " + strings.ReplaceAll(fieldError.Param(), " ", ", ")
+	case "startswith":
+		return fmt.Sprintf("must start with %q", fieldError.Param())
+	case "cron":
+		return "must be a valid cron expression"
+	case "operatingsystem":
+		return "must be a supported operating system"
+	case "role":
+		return "must be a valid role"
+	case "permission":
+		return "must be a valid permission"
+	default:
+		return "failed the " + fieldError.Tag() + " rule"
+	}
+}
+
+func lengthUnit(fieldError validator.FieldError) string {
+	switch fieldError.Kind().String() {
+	case "string":
+		return " characters"
+	case "slice", "map", "array":
+		return " items"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,120 @@
+// Package errs holds the errors use cases and handlers return to API
+// clients. Each carries a dtos.ErrorCode the error middleware maps to an
+// HTTP status; any other error is reported as INTERNAL without its message.
+package errs
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+)
+
+type Error struct {
+	Code    dtos.ErrorCode
+	Message string
+	Fields  []dtos.FieldError
+	cause   error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+func (e *Error) Status() int {
+	switch e.Code {
+	case dtos.BAD_REQUEST, dtos.VALIDATION_FAILED:
+		return http.StatusBadRequest
+	case dtos.UNAUTHORIZED:
+		return http.StatusUnauthorized
+	case dtos.FORBIDDEN:
+		return http.StatusForbidden
+	case dtos.NOT_FOUND:
+		return http.StatusNotFound
+	case dtos.CONFLICT:
+		return http.StatusConflict
+	case dtos.PAYLOAD_TOO_LARGE:
+		return http.StatusRequestEntityTooLarge
+	case dtos.UNPROCESSABLE:
+		return http.StatusUnprocessableEntity
+	case dtos.TOO_MANY_REQUESTS:
+		return http.StatusTooManyRequests
+	case dtos.UPSTREAM_UNAVAILABLE:
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// WithCause returns a copy of e wrapping cause, which is logged but not
+// shown to the client.
+func (e *Error) WithCause(cause error) *Error {
+	copy := *e
+	copy.cause = cause
+
+	return &copy
+}
+
+func New(code dtos.ErrorCode, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+func BadRequest(message string) *Error {
+	return New(dtos.BAD_REQUEST, message)
+}
+
+func Unauthorized(message string) *Error {
+	return New(dtos.UNAUTHORIZED, message)
+}
+
+func Forbidden(message string) *Error {
+	return New(dtos.FORBIDDEN, message)
+}
+
+func NotFound(message string) *Error {
+	return New(dtos.NOT_FOUND, message)
+}
+
+func Conflict(message string) *Error {
+	return New(dtos.CONFLICT, message)
+}
+
+func PayloadTooLarge(message string) *Error {
+	return New(dtos.PAYLOAD_TOO_LARGE, message)
+}
+
+func Unprocessable(message string) *Error {
+	return New(dtos.UNPROCESSABLE, message)
+}
+
+func TooManyRequests(message string) *Error {
+	return New(dtos.TOO_MANY_REQUESTS, message)
+}
+
+func UpstreamUnavailable(message string) *Error {
+	return New(dtos.UPSTREAM_UNAVAILABLE, message)
+}
+
+// Validation rejects the request because of the listed fields.
+func Validation(fields ...dtos.FieldError) *Error {
+	return &Error{Code: dtos.VALIDATION_FAILED, Message: "request validation failed", Fields: fields}
+}
+
+// Internal hides cause from the client; it is only logged.
+func Internal(cause error) *Error {
+	return &Error{Code: dtos.INTERNAL, Message: "internal server error", cause: cause}
+}
+
+// From returns the *Error in err's chain, or wraps err as Internal.
+func From(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+
+	return Internal(err)
+}
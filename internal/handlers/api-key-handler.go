@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/gin-gonic/gin"
+)
+
+type apiKeyHandler struct {
+	createApiKeyUseCase interfaces.IUseCase[dtos.CreateApiKeyDTO, dtos.CreatedApiKey]
+	findApiKeysUseCase  interfaces.IUseCase[string, []dtos.ApiKey]
+	deleteApiKeyUseCase interfaces.IUseCase[dtos.DeleteApiKeyDTO, any]
+}
+
+func NewApiKeyHandler(
+	createApiKeyUseCase interfaces.IUseCase[dtos.CreateApiKeyDTO, dtos.CreatedApiKey],
+	findApiKeysUseCase interfaces.IUseCase[string, []dtos.ApiKey],
+	deleteApiKeyUseCase interfaces.IUseCase[dtos.DeleteApiKeyDTO, any],
+) apiKeyHandler {
+	return apiKeyHandler{
+		createApiKeyUseCase: createApiKeyUseCase,
+		findApiKeysUseCase:  findApiKeysUseCase,
+		deleteApiKeyUseCase: deleteApiKeyUseCase,
+	}
+}
+
+func (h *apiKeyHandler) HandleCreateApiKey(c *gin.Context) {
+	var data dtos.CreateApiKeyDTO
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.Error(errs.Binding(err))
+		return
+	}
+	data.UserId = c.GetString("userId")
+	data.Role = c.MustGet("role").(dtos.Role)
+
+	apiKey, err := h.createApiKeyUseCase.Execute(c.Request.Context(), data)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "store this key now, it will not be shown again", apiKey)
+	c.JSON(http.StatusCreated, response)
+}
+
+func (h *apiKeyHandler) HandleGetApiKeys(c *gin.Context) {
+	apiKeys, err := h.findApiKeysUseCase.Execute(c.Request.Context(), c.GetString("userId"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", apiKeys)
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *apiKeyHandler) HandleDeleteApiKey(c *gin.Context) {
+	apiKeyId := c.Param("id")
+
+	_, err := h.deleteApiKeyUseCase.Execute(c.Request.Context(), dtos.DeleteApiKeyDTO{
+		Id:     apiKeyId,
+		UserId: c.GetString("userId"),
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", apiKeyId)
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/JMCDynamics/maestro-server/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const auditExportBatchSize = 500
+
+type auditHandler struct {
+	findAuditLogUseCase interfaces.IUseCase[dtos.FindAuditDTO, dtos.AuditPage]
+}
+
+func NewAuditHandler(
+	findAuditLogUseCase interfaces.IUseCase[dtos.FindAuditDTO, dtos.AuditPage],
+) auditHandler {
+	return auditHandler{
+		findAuditLogUseCase: findAuditLogUseCase,
+	}
+}
+
+func (h *auditHandler) HandleGetAuditLog(c *gin.Context) {
+	var data dtos.FindAuditDTO
+	if err := c.ShouldBindQuery(&data); err != nil {
+		c.Error(errs.Binding(err))
+		return
+	}
+
+	if data.Format == "csv" {
+		h.exportCSV(c, data)
+		return
+	}
+
+	page, err := h.findAuditLogUseCase.Execute(c.Request.Context(), data)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", page)
+	c.JSON(http.StatusOK, response)
+}
+
+// exportCSV streams every entry matching the filters, walking the pages
+// itself so exports are not bounded by the page size.
+func (h *auditHandler) exportCSV(c *gin.Context, data dtos.FindAuditDTO) {
+	data.Limit = auditExportBatchSize
+
+	page, err := h.findAuditLogUseCase.Execute(c.Request.Context(), data)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	filename := "audit-" + time.Now().UTC().Format("20060102T150405Z") + ".csv"
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	writer.Write([]string{"id", "created_at", "actor_id", "api_key_id", "action", "node_id", "target", "ip_address", "request_id", "outcome", "status_code"})
+
+	for {
+		for _, entry := range page.Entries {
+			writer.Write([]string{
+				entry.Id,
+				entry.CreatedAt.UTC().Format(time.RFC3339),
+				entry.ActorId,
+				entry.ApiKeyId,
+				entry.Action,
+				entry.NodeId,
+				entry.Target,
+				entry.IpAddress,
+				entry.RequestId,
+				string(entry.Outcome),
+				strconv.Itoa(entry.StatusCode),
+			})
+		}
+		writer.Flush()
+
+		if page.NextCursor == "" || c.Request.Context().Err() != nil {
+			return
+		}
+
+		data.Cursor = page.NextCursor
+		if page, err = h.findAuditLogUseCase.Execute(c.Request.Context(), data); err != nil {
+			// the status line is already sent; all we can do is stop
+			utils.Logger(c.Request.Context()).Error().Err(err).Msg("audit export interrupted")
+			return
+		}
+	}
+}
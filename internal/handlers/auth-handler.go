@@ -1,50 +1,130 @@
 package handlers
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
 	"github.com/JMCDynamics/maestro-server/internal/interfaces"
 	usecases "github.com/JMCDynamics/maestro-server/internal/use-cases"
 	"github.com/gin-gonic/gin"
 )
 
 type authHandler struct {
-	authenticateUserUseCase interfaces.IUseCase[dtos.AuthUserDTO, string]
+	authenticateUserUseCase   interfaces.IUseCase[dtos.AuthUserDTO, dtos.AuthResult]
+	logoutUseCase             interfaces.IUseCase[dtos.TokenClaims, any]
+	revokeUserSessionsUseCase interfaces.IUseCase[string, any]
+	refreshSessionUseCase     interfaces.IUseCase[dtos.RefreshSessionDTO, dtos.TokenPair]
+	verifyTwoFactorUseCase    interfaces.IUseCase[dtos.VerifyTwoFactorDTO, dtos.AuthResult]
 }
 
 func NewAuthHandler(
-	authenticateUserUseCase interfaces.IUseCase[dtos.AuthUserDTO, string],
+	authenticateUserUseCase interfaces.IUseCase[dtos.AuthUserDTO, dtos.AuthResult],
+	logoutUseCase interfaces.IUseCase[dtos.TokenClaims, any],
+	revokeUserSessionsUseCase interfaces.IUseCase[string, any],
+	refreshSessionUseCase interfaces.IUseCase[dtos.RefreshSessionDTO, dtos.TokenPair],
+	verifyTwoFactorUseCase interfaces.IUseCase[dtos.VerifyTwoFactorDTO, dtos.AuthResult],
 ) authHandler {
 	return authHandler{
-		authenticateUserUseCase: authenticateUserUseCase,
+		authenticateUserUseCase:   authenticateUserUseCase,
+		logoutUseCase:             logoutUseCase,
+		revokeUserSessionsUseCase: revokeUserSessionsUseCase,
+		refreshSessionUseCase:     refreshSessionUseCase,
+		verifyTwoFactorUseCase:    verifyTwoFactorUseCase,
 	}
 }
 
 func (h *authHandler) HandleAuth(c *gin.Context) {
 	var body dtos.AuthUserDTO
 	if err := c.ShouldBindJSON(&body); err != nil {
-		response := dtos.NewDefaultResponse("invalid request body", err.Error())
-		c.JSON(http.StatusBadRequest, response)
+		c.Error(errs.Binding(err))
 		return
 	}
+	body.UserAgent = c.Request.UserAgent()
+	body.IpAddress = c.ClientIP()
 
-	token, err := h.authenticateUserUseCase.Execute(body)
+	result, err := h.authenticateUserUseCase.Execute(c.Request.Context(), body)
 	if err != nil {
-		response := dtos.NewDefaultResponse("unable to authenticate user", err.Error())
-		status := http.StatusInternalServerError
-		if err == usecases.ErrInvalidCredentials {
-			status = http.StatusUnauthorized
-		}
-		c.JSON(status, response)
+		setRetryAfter(c, err)
+		c.Error(err)
 		return
 	}
 
-	response := dtos.NewDefaultResponse("action exectued with success", token)
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", result)
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *authHandler) HandleVerifyTwoFactor(c *gin.Context) {
+	var body dtos.VerifyTwoFactorDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.Error(errs.Binding(err))
+		return
+	}
+	body.IpAddress = c.ClientIP()
+
+	result, err := h.verifyTwoFactorUseCase.Execute(c.Request.Context(), body)
+	if err != nil {
+		setRetryAfter(c, err)
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", result)
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *authHandler) HandleRefresh(c *gin.Context) {
+	var body dtos.RefreshSessionDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.Error(errs.Binding(err))
+		return
+	}
+	body.UserAgent = c.Request.UserAgent()
+	body.IpAddress = c.ClientIP()
+
+	tokens, err := h.refreshSessionUseCase.Execute(c.Request.Context(), body)
+	if err != nil {
+		setRetryAfter(c, err)
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", tokens)
 	c.JSON(http.StatusOK, response)
 }
 
 func (h *authHandler) HandleLogout(c *gin.Context) {
-	response := dtos.NewDefaultResponse("logged out successfully", nil)
+	claims := c.MustGet("claims").(dtos.TokenClaims)
+
+	if _, err := h.logoutUseCase.Execute(c.Request.Context(), claims); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "logged out successfully", nil)
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *authHandler) HandleRevokeUserSessions(c *gin.Context) {
+	userId := c.Param("id")
+
+	_, err := h.revokeUserSessionsUseCase.Execute(c.Request.Context(), userId)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", userId)
 	c.JSON(http.StatusOK, response)
 }
+
+// setRetryAfter tells a locked out caller when it may try again.
+func setRetryAfter(c *gin.Context, err error) {
+	var throttled *usecases.LoginThrottledError
+	if errors.As(err, &throttled) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+	}
+}
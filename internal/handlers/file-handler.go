@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/JMCDynamics/maestro-server/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+var errFileTooLarge = errs.PayloadTooLarge("file exceeds the maximum allowed size")
+
+type fileHandler struct {
+	maxFileSize             int64
+	listNodeFilesUseCase    interfaces.IUseCase[dtos.NodeFileDTO, []dtos.FileEntry]
+	statNodeFileUseCase     interfaces.IUseCase[dtos.NodeFileDTO, dtos.FileEntry]
+	uploadNodeFileUseCase   interfaces.IUseCase[dtos.UploadFileDTO, dtos.FileTransfer]
+	downloadNodeFileUseCase interfaces.IUseCase[dtos.DownloadFileDTO, dtos.FileStream]
+}
+
+func NewFileHandler(
+	maxFileSize int64,
+	listNodeFilesUseCase interfaces.IUseCase[dtos.NodeFileDTO, []dtos.FileEntry],
+	statNodeFileUseCase interfaces.IUseCase[dtos.NodeFileDTO, dtos.FileEntry],
+	uploadNodeFileUseCase interfaces.IUseCase[dtos.UploadFileDTO, dtos.FileTransfer],
+	downloadNodeFileUseCase interfaces.IUseCase[dtos.DownloadFileDTO, dtos.FileStream],
+) fileHandler {
+	return fileHandler{
+		maxFileSize:             maxFileSize,
+		listNodeFilesUseCase:    listNodeFilesUseCase,
+		statNodeFileUseCase:     statNodeFileUseCase,
+		uploadNodeFileUseCase:   uploadNodeFileUseCase,
+		downloadNodeFileUseCase: downloadNodeFileUseCase,
+	}
+}
+
+func (h *fileHandler) HandleListFiles(c *gin.Context) {
+	var data dtos.NodeFileDTO
+	if err := c.ShouldBindQuery(&data); err != nil {
+		c.Error(errs.Binding(err))
+		return
+	}
+	data.NodeId = c.Param("id")
+	data.Scope = nodeScope(c)
+
+	entries, err := h.listNodeFilesUseCase.Execute(c.Request.Context(), data)
+	if err != nil {
+		c.Error(fileError(err))
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", entries)
+	c.JSON(http.StatusOK, response)
+}
+
+// HandleStatFile answers HEAD requests so clients can find out how many bytes
+// of an interrupted upload already reached the node before resuming it.
+func (h *fileHandler) HandleStatFile(c *gin.Context) {
+	var data dtos.NodeFileDTO
+	if err := c.ShouldBindQuery(&data); err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	data.NodeId = c.Param("id")
+	data.Scope = nodeScope(c)
+
+	entry, err := h.statNodeFileUseCase.Execute(c.Request.Context(), data)
+	if err != nil {
+		c.Status(errs.From(fileError(err)).Status())
+		return
+	}
+
+	c.Header("Content-Length", strconv.FormatInt(entry.Size, 10))
+	c.Header("Last-Modified", entry.ModifiedAt.UTC().Format(http.TimeFormat))
+	c.Header("Accept-Ranges", "bytes")
+	c.Status(http.StatusOK)
+}
+
+func (h *fileHandler) HandleUploadFile(c *gin.Context) {
+	var query dtos.NodeFileDTO
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(errs.Binding(err))
+		return
+	}
+
+	data := dtos.UploadFileDTO{
+		NodeId: c.Param("id"),
+		Scope:  nodeScope(c),
+		Path:   query.Path,
+		Sha256: c.GetHeader("X-Content-Sha256"),
+	}
+
+	limit := h.maxFileSize
+	if contentRange := c.GetHeader("Content-Range"); contentRange != "" {
+		var end int64
+		if _, err := fmt.Sscanf(contentRange, "bytes %d-%d/%d", &data.Offset, &end, &data.Total); err != nil || data.Offset > end || end >= data.Total {
+			c.Error(errs.BadRequest("invalid Content-Range header"))
+			return
+		}
+
+		if data.Total > h.maxFileSize {
+			c.Error(errFileTooLarge)
+			return
+		}
+
+		data.Length = end - data.Offset + 1
+		limit = data.Length
+	}
+
+	if c.Request.ContentLength > limit {
+		c.Error(errFileTooLarge)
+		return
+	}
+
+	data.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+
+	transfer, err := h.uploadNodeFileUseCase.Execute(c.Request.Context(), data)
+	if err != nil {
+		c.Error(fileError(err))
+		return
+	}
+
+	status := http.StatusOK
+	if !transfer.Complete {
+		status = http.StatusAccepted
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", transfer)
+	c.JSON(status, response)
+}
+
+func (h *fileHandler) HandleDownloadFile(c *gin.Context) {
+	var query dtos.NodeFileDTO
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(errs.Binding(err))
+		return
+	}
+
+	stream, err := h.downloadNodeFileUseCase.Execute(c.Request.Context(), dtos.DownloadFileDTO{
+		NodeId: c.Param("id"),
+		Scope:  nodeScope(c),
+		Path:   query.Path,
+		Range:  c.GetHeader("Range"),
+	})
+	if err != nil {
+		c.Error(fileError(err))
+		return
+	}
+	defer stream.Body.Close()
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", stream.ContentType)
+	header.Set("Accept-Ranges", "bytes")
+	if stream.ContentLength >= 0 {
+		header.Set("Content-Length", strconv.FormatInt(stream.ContentLength, 10))
+	}
+	if stream.ContentRange != "" {
+		header.Set("Content-Range", stream.ContentRange)
+	}
+
+	if stream.Sha256 != "" {
+		header.Set("X-Content-Sha256", stream.Sha256)
+	}
+
+	c.Status(stream.StatusCode)
+
+	if _, err := io.Copy(c.Writer, stream.Body); err != nil {
+		utils.Logger(c.Request.Context()).Warn().Err(err).Msg("unable to stream file from node")
+	}
+}
+
+// fileError reports a body cut off by MaxBytesReader as too large rather
+// than as a failed transfer.
+func fileError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return errFileTooLarge
+	}
+
+	return err
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/JMCDynamics/maestro-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type healthHandler struct {
+	checkReadinessUseCase interfaces.IUseCase[any, dtos.Readiness]
+	shutdownService       *services.ShutdownService
+}
+
+func NewHealthHandler(
+	checkReadinessUseCase interfaces.IUseCase[any, dtos.Readiness],
+	shutdownService *services.ShutdownService,
+) healthHandler {
+	return healthHandler{
+		checkReadinessUseCase: checkReadinessUseCase,
+		shutdownService:       shutdownService,
+	}
+}
+
+// HandleHealthz answers as long as the process serves requests.
+func (h *healthHandler) HandleHealthz(c *gin.Context) {
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "alive", nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// HandleReadyz answers 503 while a dependency is down or the server is
+// shutting down, so load balancers stop routing to it.
+func (h *healthHandler) HandleReadyz(c *gin.Context) {
+	select {
+	case <-h.shutdownService.Done():
+		response := dtos.NewDefaultResponse(c.GetString("requestId"), "shutting down", nil)
+		c.JSON(http.StatusServiceUnavailable, response)
+		return
+	default:
+	}
+
+	readiness, err := h.checkReadinessUseCase.Execute(c.Request.Context(), nil)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if readiness.Status != dtos.CHECK_UP {
+		response := dtos.NewDefaultResponse(c.GetString("requestId"), "not ready", readiness)
+		c.JSON(http.StatusServiceUnavailable, response)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "ready", readiness)
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/JMCDynamics/maestro-server/internal/services"
+	"github.com/JMCDynamics/maestro-server/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type jobHandler struct {
+	execCommandUseCase interfaces.IUseCase[dtos.ExecDTO, dtos.Job]
+	findJobsUseCase    interfaces.IUseCase[dtos.FindJobsDTO, []dtos.Job]
+	findJobUseCase     interfaces.IUseCase[dtos.JobAccessDTO, dtos.Job]
+	cancelJobUseCase   interfaces.IUseCase[dtos.JobAccessDTO, any]
+	shutdownService    *services.ShutdownService
+}
+
+func NewJobHandler(
+	execCommandUseCase interfaces.IUseCase[dtos.ExecDTO, dtos.Job],
+	findJobsUseCase interfaces.IUseCase[dtos.FindJobsDTO, []dtos.Job],
+	findJobUseCase interfaces.IUseCase[dtos.JobAccessDTO, dtos.Job],
+	cancelJobUseCase interfaces.IUseCase[dtos.JobAccessDTO, any],
+	shutdownService *services.ShutdownService,
+) jobHandler {
+	return jobHandler{
+		execCommandUseCase: execCommandUseCase,
+		findJobsUseCase:    findJobsUseCase,
+		findJobUseCase:     findJobUseCase,
+		cancelJobUseCase:   cancelJobUseCase,
+		shutdownService:    shutdownService,
+	}
+}
+
+type execEvent struct {
+	name string
+	data any
+}
+
+type execResult struct {
+	job dtos.Job
+	err error
+}
+
+func (h *jobHandler) HandleExec(c *gin.Context) {
+	var data dtos.ExecDTO
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.Error(errs.Binding(err))
+		return
+	}
+
+	data.NodeId = c.Param("id")
+	data.UserId = c.GetString("userId")
+	data.Scope = nodeScope(c)
+
+	// The job runs on its own goroutine so the stream can end on shutdown or
+	// disconnect while the job carries on; its events are dropped after that.
+	events := make(chan execEvent)
+	stopped := make(chan struct{})
+	defer close(stopped)
+
+	send := func(name string, data any) {
+		select {
+		case events <- execEvent{name: name, data: data}:
+		case <-stopped:
+		}
+	}
+	data.OnStart = func(job dtos.Job) {
+		send("start", job)
+	}
+	data.OnOutput = func(output dtos.JobOutput) {
+		send(output.Stream, output)
+	}
+
+	ctx := c.Request.Context()
+	results := make(chan execResult, 1)
+	go func() {
+		job, err := h.execCommandUseCase.Execute(ctx, data)
+		results <- execResult{job: job, err: err}
+	}()
+
+	// shutdown only ends streams; a job that has not started yet still
+	// answers the request
+	var shutdown <-chan struct{}
+	for {
+		select {
+		case event := <-events:
+			if shutdown == nil {
+				shutdown = h.shutdownService.Done()
+
+				c.Writer.Header().Set("Content-Type", "text/event-stream")
+				c.Writer.Header().Set("Cache-Control", "no-cache")
+				c.Writer.Header().Set("Connection", "keep-alive")
+				c.Writer.Header().Set("X-Accel-Buffering", "no")
+				c.Status(http.StatusOK)
+			}
+
+			writeEvent(c, event.name, event.data)
+		case result := <-results:
+			if shutdown == nil {
+				c.Error(result.err)
+				return
+			}
+
+			if result.err != nil {
+				utils.Logger(ctx).Error().Err(result.err).Str("job-id", result.job.Id).Msg("unable to finish job")
+			}
+
+			writeEvent(c, "done", result.job)
+			return
+		case <-shutdown:
+			writeShutdownEvent(c)
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (h *jobHandler) HandleGetJobs(c *gin.Context) {
+	var data dtos.FindJobsDTO
+	if err := c.ShouldBindQuery(&data); err != nil {
+		c.Error(errs.Binding(err))
+		return
+	}
+	data.Scope = nodeScope(c)
+
+	jobs, err := h.findJobsUseCase.Execute(c.Request.Context(), data)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", jobs)
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *jobHandler) HandleGetJob(c *gin.Context) {
+	job, err := h.findJobUseCase.Execute(c.Request.Context(), dtos.JobAccessDTO{
+		Scope: nodeScope(c),
+		JobId: c.Param("id"),
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", job)
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *jobHandler) HandleCancelJob(c *gin.Context) {
+	jobId := c.Param("id")
+
+	_, err := h.cancelJobUseCase.Execute(c.Request.Context(), dtos.JobAccessDTO{
+		Scope: nodeScope(c),
+		JobId: jobId,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", jobId)
+	c.JSON(http.StatusAccepted, response)
+}
+
+func writeEvent(c *gin.Context, event string, data any) {
+	dataJson, err := json.Marshal(data)
+	if err != nil {
+		utils.Logger(c.Request.Context()).Error().Err(err).Str("event", event).Msg("unable to serialize event")
+		return
+	}
+
+	fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", event, dataJson)
+	c.Writer.Flush()
+}
+
+// writeShutdownEvent tells a stream's client that the server is stopping and
+// it should reconnect, possibly to another instance.
+func writeShutdownEvent(c *gin.Context) {
+	writeEvent(c, "shutdown", gin.H{"message": "server is shutting down"})
+}
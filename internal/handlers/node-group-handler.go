@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/gin-gonic/gin"
+)
+
+type nodeGroupHandler struct {
+	createNodeGroupUseCase interfaces.IUseCase[dtos.NodeGroupDTO, dtos.NodeGroup]
+	findNodeGroupsUseCase  interfaces.IUseCase[any, []dtos.NodeGroup]
+	findNodeGroupUseCase   interfaces.IUseCase[string, dtos.NodeGroup]
+	updateNodeGroupUseCase interfaces.IUseCase[dtos.NodeGroupDTO, dtos.NodeGroup]
+	deleteNodeGroupUseCase interfaces.IUseCase[string, any]
+}
+
+func NewNodeGroupHandler(
+	createNodeGroupUseCase interfaces.IUseCase[dtos.NodeGroupDTO, dtos.NodeGroup],
+	findNodeGroupsUseCase interfaces.IUseCase[any, []dtos.NodeGroup],
+	findNodeGroupUseCase interfaces.IUseCase[string, dtos.NodeGroup],
+	updateNodeGroupUseCase interfaces.IUseCase[dtos.NodeGroupDTO, dtos.NodeGroup],
+	deleteNodeGroupUseCase interfaces.IUseCase[string, any],
+) nodeGroupHandler {
+	return nodeGroupHandler{
+		createNodeGroupUseCase: createNodeGroupUseCase,
+		findNodeGroupsUseCase:  findNodeGroupsUseCase,
+		findNodeGroupUseCase:   findNodeGroupUseCase,
+		updateNodeGroupUseCase: updateNodeGroupUseCase,
+		deleteNodeGroupUseCase: deleteNodeGroupUseCase,
+	}
+}
+
+func (h *nodeGroupHandler) HandleCreateNodeGroup(c *gin.Context) {
+	var data dtos.NodeGroupDTO
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.Error(errs.Binding(err))
+		return
+	}
+
+	group, err := h.createNodeGroupUseCase.Execute(c.Request.Context(), data)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", group)
+	c.JSON(http.StatusCreated, response)
+}
+
+func (h *nodeGroupHandler) HandleGetNodeGroups(c *gin.Context) {
+	groups, err := h.findNodeGroupsUseCase.Execute(c.Request.Context(), nil)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", groups)
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *nodeGroupHandler) HandleGetNodeGroup(c *gin.Context) {
+	group, err := h.findNodeGroupUseCase.Execute(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", group)
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *nodeGroupHandler) HandleUpdateNodeGroup(c *gin.Context) {
+	var data dtos.NodeGroupDTO
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.Error(errs.Binding(err))
+		return
+	}
+	data.Id = c.Param("id")
+
+	group, err := h.updateNodeGroupUseCase.Execute(c.Request.Context(), data)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", group)
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *nodeGroupHandler) HandleDeleteNodeGroup(c *gin.Context) {
+	groupId := c.Param("id")
+
+	if _, err := h.deleteNodeGroupUseCase.Execute(c.Request.Context(), groupId); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", groupId)
+	c.JSON(http.StatusOK, response)
+}
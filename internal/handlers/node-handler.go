@@ -6,92 +6,126 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"time"
 
 	"maps"
 
 	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
 	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/JMCDynamics/maestro-server/internal/middlewares"
 	"github.com/JMCDynamics/maestro-server/internal/services"
 	usecases "github.com/JMCDynamics/maestro-server/internal/use-cases"
+	"github.com/JMCDynamics/maestro-server/internal/utils"
 	"github.com/gin-gonic/gin"
 )
 
+var errPathRequired = errs.Validation(dtos.FieldError{Field: "path", Rule: "required", Message: "is required"})
+
 type nodeHandler struct {
-	findNodesUseCase  interfaces.IUseCase[any, []dtos.Node]
-	findNodeUseCase   interfaces.IUseCase[string, dtos.Node]
-	createNodeUseCase interfaces.IUseCase[dtos.CreateNodeDTO, dtos.Node]
-	setNodeUpUseCase  interfaces.IUseCase[string, any]
-	nodeStatusService *services.NodeStatusService
-	updateNodeUseCase interfaces.IUseCase[dtos.UpdateNodeDTO, dtos.Node]
+	findNodesUseCase     interfaces.IUseCase[dtos.NodeScope, []dtos.Node]
+	findNodeUseCase      interfaces.IUseCase[string, dtos.Node]
+	createNodeUseCase    interfaces.IUseCase[dtos.CreateNodeDTO, dtos.Node]
+	setNodeUpUseCase     interfaces.IUseCase[string, any]
+	nodeStatusService    *services.NodeStatusService
+	shutdownService      *services.ShutdownService
+	updateNodeUseCase    interfaces.IUseCase[dtos.UpdateNodeDTO, dtos.Node]
+	broadcastUseCase     interfaces.IUseCase[dtos.BroadcastDTO, map[string]dtos.BroadcastResult]
+	deleteNodeUseCase    interfaces.IUseCase[dtos.NodeAccessDTO, any]
+	canAccessNodeUseCase interfaces.IUseCase[dtos.NodeAccessDTO, bool]
 }
 
 func NewNodeHandler(
-	findNodesUseCase interfaces.IUseCase[any, []dtos.Node],
+	findNodesUseCase interfaces.IUseCase[dtos.NodeScope, []dtos.Node],
 	createNodeUseCase interfaces.IUseCase[dtos.CreateNodeDTO, dtos.Node],
 	findNodeUseCase interfaces.IUseCase[string, dtos.Node],
 	setNodeUpUseCase interfaces.IUseCase[string, any],
 	nodeStatusService *services.NodeStatusService,
+	shutdownService *services.ShutdownService,
 	updateNodeUseCase interfaces.IUseCase[dtos.UpdateNodeDTO, dtos.Node],
+	broadcastUseCase interfaces.IUseCase[dtos.BroadcastDTO, map[string]dtos.BroadcastResult],
+	deleteNodeUseCase interfaces.IUseCase[dtos.NodeAccessDTO, any],
+	canAccessNodeUseCase interfaces.IUseCase[dtos.NodeAccessDTO, bool],
 ) nodeHandler {
 	return nodeHandler{
-		findNodesUseCase:  findNodesUseCase,
-		createNodeUseCase: createNodeUseCase,
-		findNodeUseCase:   findNodeUseCase,
-		setNodeUpUseCase:  setNodeUpUseCase,
-		nodeStatusService: nodeStatusService,
-		updateNodeUseCase: updateNodeUseCase,
+		findNodesUseCase:     findNodesUseCase,
+		createNodeUseCase:    createNodeUseCase,
+		findNodeUseCase:      findNodeUseCase,
+		setNodeUpUseCase:     setNodeUpUseCase,
+		nodeStatusService:    nodeStatusService,
+		shutdownService:      shutdownService,
+		updateNodeUseCase:    updateNodeUseCase,
+		broadcastUseCase:     broadcastUseCase,
+		deleteNodeUseCase:    deleteNodeUseCase,
+		canAccessNodeUseCase: canAccessNodeUseCase,
 	}
 }
 
+func nodeScope(c *gin.Context) dtos.NodeScope {
+	scopes, _ := c.Get("scopes")
+	s, _ := scopes.([]dtos.Permission)
+
+	return dtos.NewNodeScope(c.GetString("userId"), c.MustGet("role").(dtos.Role), s)
+}
+
 func (h *nodeHandler) HandleGetNodes(c *gin.Context) {
-	nodes, err := h.findNodesUseCase.Execute(nil)
+	nodes, err := h.findNodesUseCase.Execute(c.Request.Context(), nodeScope(c))
 	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	response := dtos.NewDefaultResponse("action exectued with success", nodes)
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", nodes)
 	c.JSON(http.StatusOK, response)
 }
 
 func (h *nodeHandler) HandleGetNode(c *gin.Context) {
 	nodeId := c.Param("id")
-	nodes, err := h.findNodeUseCase.Execute(nodeId)
+	node, err := h.findNodeUseCase.Execute(c.Request.Context(), nodeId)
 	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	response := dtos.NewDefaultResponse("action exectued with success", nodes)
+	// The VPN config embeds the peer's private key.
+	if !middlewares.HasPermission(c, dtos.PERM_NODES_VPN_CONFIG) {
+		node.VpnConfig = ""
+	} else {
+		middlewares.SetAuditAction(c, "node.vpn-config.read")
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", node)
 	c.JSON(http.StatusOK, response)
 }
 
 func (h *nodeHandler) HandleCreateNode(c *gin.Context) {
-	nodes, err := h.findNodesUseCase.Execute(nil)
+	nodes, err := h.findNodesUseCase.Execute(c.Request.Context(), dtos.NodeScope{All: true})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dtos.NewDefaultResponse("failed to find nodes", nil))
+		c.Error(err)
 		return
 	}
 
 	if len(nodes) >= 4 {
-		c.JSON(http.StatusForbidden, dtos.NewDefaultResponse("maximum number of nodes reached", nil))
+		c.Error(errs.Forbidden("maximum number of nodes reached"))
 		return
 	}
 
 	var data dtos.CreateNodeDTO
 	if err := c.ShouldBindJSON(&data); err != nil {
-		c.JSON(http.StatusBadRequest, dtos.NewDefaultResponse(err.Error(), nil))
+		c.Error(errs.Binding(err))
 		return
 	}
 
-	node, err := h.createNodeUseCase.Execute(data)
+	data.Scope = nodeScope(c)
+
+	node, err := h.createNodeUseCase.Execute(c.Request.Context(), data)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dtos.NewDefaultResponse("failed to create node", nil))
+		c.Error(err)
 		return
 	}
 
-	response := dtos.NewDefaultResponse("action exectued with success", node)
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", node)
 	c.JSON(http.StatusCreated, response)
 }
 
@@ -99,38 +133,28 @@ func (h *nodeHandler) HandleNodeProxySSE(c *gin.Context) {
 	nodeId := c.Param("id")
 	path := c.Query("path")
 	if path == "" {
-		response := dtos.NewDefaultResponse("param path is empty", nil)
-		c.JSON(http.StatusBadRequest, response)
-		return
-	}
-
-	node, err := h.findNodeUseCase.Execute(nodeId)
-	if err == usecases.ErrNodeNotFound {
-		response := dtos.NewDefaultResponse(err.Error(), nil)
-		c.JSON(http.StatusNotFound, response)
+		c.Error(errPathRequired)
 		return
 	}
 
+	node, err := h.findNodeUseCase.Execute(c.Request.Context(), nodeId)
 	if err != nil {
-		response := dtos.NewDefaultResponse("unable to find node", nil)
-		c.JSON(http.StatusInternalServerError, response)
+		c.Error(err)
 		return
 	}
 
-	sseSourceURL := fmt.Sprintf("http://%s:%s%s", node.VpnAddress, "9842", path)
+	sseSourceURL := utils.NodeAgentURL(node.VpnAddress, path)
 
-	client := &http.Client{
-		Timeout: 0,
-	}
-	req, err := http.NewRequest("GET", sseSourceURL, nil)
+	client := utils.NewNodeAgentClient(0)
+	req, err := utils.NewNodeAgentRequest(c.Request.Context(), "GET", sseSourceURL, nil)
 	if err != nil {
-		c.String(http.StatusInternalServerError, "Erro ao criar requisição: %s", err)
+		c.Error(err)
 		return
 	}
 
 	resp, err := client.Do(req)
 	if err != nil {
-		c.String(http.StatusInternalServerError, "Erro ao conectar ao servidor SSE: %s", err)
+		c.Error(usecases.ErrNodeAgentUnavailable)
 		return
 	}
 	defer resp.Body.Close()
@@ -140,14 +164,19 @@ func (h *nodeHandler) HandleNodeProxySSE(c *gin.Context) {
 	c.Writer.Header().Set("Connection", "keep-alive")
 	c.Writer.Header().Set("X-Accel-Buffering", "no")
 
+	logger := utils.Logger(c.Request.Context())
+
 	ctx, cancel := context.WithCancel(c.Request.Context())
 	defer cancel()
 
-	done := make(chan struct{})
+	done := make(chan struct{}, 1)
 
 	go func() {
-		<-ctx.Done()
-		log.Println("Conexão cliente cancelada")
+		select {
+		case <-ctx.Done():
+			logger.Debug().Msg("sse proxy client disconnected")
+		case <-h.shutdownService.Done():
+		}
 		cancel()
 		if resp.Body != nil {
 			resp.Body.Close()
@@ -165,62 +194,57 @@ loop:
 		default:
 			line, err := reader.ReadBytes('\n')
 			if err != nil {
-				if err != io.EOF {
-					log.Printf("Error reading stream: %v", err)
+				select {
+				case <-h.shutdownService.Done():
+					writeShutdownEvent(c)
+				default:
+					if err != io.EOF {
+						logger.Warn().Err(err).Msg("unable to read node event stream")
+					}
 				}
 				return
 			}
 
 			if _, err := c.Writer.Write(line); err != nil {
-				log.Printf("Error writing to client: %v", err)
+				logger.Debug().Err(err).Msg("unable to write event to client")
 				return
 			}
 			c.Writer.Flush()
 		}
 	}
 
-	fmt.Println("finished sse proxy")
+	logger.Debug().Msg("finished sse proxy")
 }
 
 func (h *nodeHandler) HandleNodeProxy(c *gin.Context) {
 	nodeId := c.Param("id")
 	path := c.Query("path")
 	if path == "" {
-		response := dtos.NewDefaultResponse("param path is empty", nil)
-		c.JSON(http.StatusBadRequest, response)
-		return
-	}
-
-	node, err := h.findNodeUseCase.Execute(nodeId)
-	if err == usecases.ErrNodeNotFound {
-		response := dtos.NewDefaultResponse(err.Error(), nil)
-		c.JSON(http.StatusNotFound, response)
+		c.Error(errPathRequired)
 		return
 	}
 
+	node, err := h.findNodeUseCase.Execute(c.Request.Context(), nodeId)
 	if err != nil {
-		response := dtos.NewDefaultResponse("unable to find node", nil)
-		c.JSON(http.StatusInternalServerError, response)
+		c.Error(err)
 		return
 	}
 
-	targetURL := fmt.Sprintf("http://%s:%s%s", node.VpnAddress, "9842", path)
+	targetURL := utils.NodeAgentURL(node.VpnAddress, path)
 
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
+	client := utils.NewNodeAgentClient(30 * time.Second)
 
-	req, err := http.NewRequest(c.Request.Method, targetURL, c.Request.Body)
+	req, err := utils.NewNodeAgentRequest(c.Request.Context(), c.Request.Method, targetURL, c.Request.Body)
 	if err != nil {
-		response := dtos.NewDefaultResponse("unable to create a request", nil)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		c.Error(err)
+		c.Abort()
 		return
 	}
 
 	resp, err := client.Do(req)
 	if err != nil {
-		response := dtos.NewDefaultResponse("failed to connect to the node", nil)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		c.Error(usecases.ErrNodeAgentUnavailable)
+		c.Abort()
 		return
 	}
 	defer resp.Body.Close()
@@ -231,24 +255,22 @@ func (h *nodeHandler) HandleNodeProxy(c *gin.Context) {
 
 	_, err = io.Copy(c.Writer, resp.Body)
 	if err != nil {
-		log.Printf("Error copying response body: %v", err)
+		utils.Logger(c.Request.Context()).Warn().Err(err).Msg("unable to copy node response body")
 	}
 }
 
 func (h *nodeHandler) HandleUpdateStatusNode(c *gin.Context) {
 	nodeId := c.Param("id")
 
-	node, err := h.findNodeUseCase.Execute(nodeId)
-	if err == usecases.ErrNodeNotFound {
-		response := dtos.NewDefaultResponse("unable to find node", nil)
-		c.JSON(http.StatusUnauthorized, response)
+	node, err := h.findNodeUseCase.Execute(c.Request.Context(), nodeId)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	_, err = h.setNodeUpUseCase.Execute(node.Id)
+	_, err = h.setNodeUpUseCase.Execute(c.Request.Context(), node.Id)
 	if err != nil {
-		response := dtos.NewDefaultResponse(err.Error(), nil)
-		c.JSON(http.StatusInternalServerError, response)
+		c.Error(err)
 		return
 	}
 
@@ -257,41 +279,45 @@ func (h *nodeHandler) HandleUpdateStatusNode(c *gin.Context) {
 		Status: dtos.UP,
 	})
 
-	response := dtos.NewDefaultResponse("action exectued with success", nodeId)
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", nodeId)
 	c.JSON(http.StatusOK, response)
 }
 
 func (h *nodeHandler) HandleListenNodesStatus(c *gin.Context) {
+	scope := nodeScope(c)
+	logger := utils.Logger(c.Request.Context())
+
 	c.Writer.Header().Set("Content-Type", "text/event-stream")
 	c.Writer.Header().Set("Cache-Control", "no-cache")
 	c.Writer.Header().Set("Connection", "keep-alive")
 
-	ctx, cancel := context.WithCancel(c.Request.Context())
-	defer cancel()
-
-	done := make(chan struct{})
-
-	go func() {
-		<-ctx.Done()
-		log.Println("Conexão cliente cancelada")
-		cancel()
-		done <- struct{}{}
-	}()
+	statuses, unsubscribe := h.nodeStatusService.Subscribe()
+	defer unsubscribe()
 
 	for {
 		select {
-		case <-done:
-			log.Println("Finalizando conexão devido ao cancelamento do contexto.")
+		case <-c.Request.Context().Done():
+			logger.Debug().Msg("node status client disconnected")
+			return
+		case <-h.shutdownService.Done():
+			writeShutdownEvent(c)
 			return
-		case nodeStatus, ok := <-h.nodeStatusService.ListenStatus():
+		case nodeStatus, ok := <-statuses:
 			if !ok {
-				log.Println("Canal de status fechado")
+				logger.Debug().Msg("node status channel closed")
 				return
 			}
 
+			if !scope.All {
+				allowed, err := h.canAccessNodeUseCase.Execute(c.Request.Context(), dtos.NodeAccessDTO{Scope: scope, NodeId: nodeStatus.Id})
+				if err != nil || !allowed {
+					continue
+				}
+			}
+
 			dataJson, err := json.Marshal(nodeStatus)
 			if err != nil {
-				log.Printf("Erro ao serializar status do nó: %s", err)
+				logger.Error().Err(err).Msg("unable to serialize node status")
 				return
 			}
 
@@ -306,20 +332,98 @@ func (h *nodeHandler) HandleUpdateNode(c *gin.Context) {
 
 	var data dtos.UpdateNodeDTO
 	if err := c.ShouldBindJSON(&data); err != nil {
-		response := dtos.NewDefaultResponse(err.Error(), nil)
-		c.JSON(http.StatusBadRequest, response)
+		c.Error(errs.Binding(err))
 		return
 	}
 
 	data.Id = nodeId
+	data.Scope = nodeScope(c)
+
+	node, err := h.updateNodeUseCase.Execute(c.Request.Context(), data)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", node)
+	c.JSON(http.StatusOK, response)
+}
 
-	node, err := h.updateNodeUseCase.Execute(data)
+func (h *nodeHandler) HandleDeleteNode(c *gin.Context) {
+	nodeId := c.Param("id")
+
+	_, err := h.deleteNodeUseCase.Execute(c.Request.Context(), dtos.NodeAccessDTO{
+		Scope:  nodeScope(c),
+		NodeId: nodeId,
+	})
 	if err != nil {
-		response := dtos.NewDefaultResponse(err.Error(), nil)
-		c.JSON(http.StatusInternalServerError, response)
+		c.Error(err)
 		return
 	}
 
-	response := dtos.NewDefaultResponse("action exectued with success", node)
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", nodeId)
 	c.JSON(http.StatusOK, response)
 }
+
+func (h *nodeHandler) HandleBroadcast(c *gin.Context) {
+	var data dtos.BroadcastDTO
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.Error(errs.Binding(err))
+		return
+	}
+	data.Scope = nodeScope(c)
+
+	if c.Query("stream") != "true" {
+		results, err := h.broadcastUseCase.Execute(c.Request.Context(), data)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", results)
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.Header().Set("Connection", "keep-alive")
+	c.Writer.Header().Set("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	// shutdown aborts the requests still waiting on nodes
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-h.shutdownService.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	data.OnResult = func(result dtos.BroadcastResult) {
+		if ctx.Err() != nil {
+			return
+		}
+		writeEvent(c, "result", result)
+	}
+
+	results, err := h.broadcastUseCase.Execute(ctx, data)
+
+	select {
+	case <-h.shutdownService.Done():
+		writeShutdownEvent(c)
+		return
+	default:
+	}
+
+	if err != nil {
+		writeEvent(c, "error", "unable to broadcast request")
+		return
+	}
+
+	writeEvent(c, "done", gin.H{"total": len(results)})
+}
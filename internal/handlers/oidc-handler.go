@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/JMCDynamics/maestro-server/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	oidcStateCookie = "maestro_oidc_state"
+	// oidcStateCookieMaxAge matches how long the login state is kept.
+	oidcStateCookieMaxAge = 10 * 60
+	oidcCallbackPath      = "/auth/oidc/callback"
+)
+
+type oidcHandler struct {
+	postLoginURL             string
+	startOidcLoginUseCase    interfaces.IUseCase[any, dtos.OidcLogin]
+	completeOidcLoginUseCase interfaces.IUseCase[dtos.OidcCallbackDTO, dtos.TokenPair]
+}
+
+// NewOidcHandler redirects the browser to postLoginURL after a successful
+// login, with the tokens in the URL fragment. Without it the callback
+// answers with JSON like POST /auth.
+func NewOidcHandler(
+	postLoginURL string,
+	startOidcLoginUseCase interfaces.IUseCase[any, dtos.OidcLogin],
+	completeOidcLoginUseCase interfaces.IUseCase[dtos.OidcCallbackDTO, dtos.TokenPair],
+) oidcHandler {
+	return oidcHandler{
+		postLoginURL:             postLoginURL,
+		startOidcLoginUseCase:    startOidcLoginUseCase,
+		completeOidcLoginUseCase: completeOidcLoginUseCase,
+	}
+}
+
+func (h *oidcHandler) HandleLogin(c *gin.Context) {
+	login, err := h.startOidcLoginUseCase.Execute(c.Request.Context(), nil)
+	if err != nil {
+		c.Error(errs.UpstreamUnavailable("unable to start single sign-on").WithCause(err))
+		return
+	}
+
+	// The state only completes a login in the browser that started it, so
+	// a callback URL planted in someone else's browser is refused.
+	setOidcStateCookie(c, login.State, oidcStateCookieMaxAge)
+	c.Redirect(http.StatusFound, login.AuthURL)
+}
+
+func (h *oidcHandler) HandleCallback(c *gin.Context) {
+	cookieState, _ := c.Cookie(oidcStateCookie)
+	setOidcStateCookie(c, "", -1)
+
+	if providerErr := c.Query("error"); providerErr != "" {
+		utils.Logger(c.Request.Context()).Warn().
+			Str("error", providerErr).
+			Str("description", c.Query("error_description")).
+			Msg("single sign-on rejected by the provider")
+		c.Error(errs.Unauthorized("single sign-on was rejected by the provider"))
+		return
+	}
+
+	var query dtos.OidcCallbackDTO
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(errs.Binding(err))
+		return
+	}
+
+	if cookieState == "" || subtle.ConstantTimeCompare([]byte(cookieState), []byte(query.State)) != 1 {
+		c.Error(errs.Unauthorized("single sign-on was not started in this browser"))
+		return
+	}
+	query.UserAgent = c.Request.UserAgent()
+	query.IpAddress = c.ClientIP()
+
+	tokens, err := h.completeOidcLoginUseCase.Execute(c.Request.Context(), query)
+	if err != nil {
+		// failed code exchanges and token checks are the provider's answer,
+		// not a server fault
+		if errs.From(err).Code == dtos.INTERNAL {
+			err = errs.Unauthorized("unable to complete single sign-on").WithCause(err)
+		}
+		c.Error(err)
+		return
+	}
+
+	if h.postLoginURL == "" {
+		response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", tokens)
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	// The fragment never reaches server logs or the Referer header.
+	fragment := url.Values{}
+	fragment.Set("accessToken", tokens.AccessToken)
+	fragment.Set("accessTokenExpiresAt", strconv.FormatInt(tokens.AccessTokenExpiresAt.Unix(), 10))
+	fragment.Set("refreshToken", tokens.RefreshToken)
+	fragment.Set("sessionId", tokens.SessionId)
+
+	c.Redirect(http.StatusFound, h.postLoginURL+"#"+fragment.Encode())
+}
+
+func setOidcStateCookie(c *gin.Context, state string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, state, maxAge, oidcCallbackPath, "", c.Request.TLS != nil, true)
+}
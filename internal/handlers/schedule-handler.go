@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/gin-gonic/gin"
+)
+
+type scheduleHandler struct {
+	createScheduleUseCase   interfaces.IUseCase[dtos.ScheduleDTO, dtos.Schedule]
+	findSchedulesUseCase    interfaces.IUseCase[dtos.NodeScope, []dtos.Schedule]
+	findScheduleUseCase     interfaces.IUseCase[dtos.ScheduleAccessDTO, dtos.Schedule]
+	updateScheduleUseCase   interfaces.IUseCase[dtos.ScheduleDTO, dtos.Schedule]
+	deleteScheduleUseCase   interfaces.IUseCase[dtos.ScheduleAccessDTO, any]
+	findScheduleRunsUseCase interfaces.IUseCase[dtos.FindScheduleRunsDTO, []dtos.ScheduleRun]
+}
+
+func NewScheduleHandler(
+	createScheduleUseCase interfaces.IUseCase[dtos.ScheduleDTO, dtos.Schedule],
+	findSchedulesUseCase interfaces.IUseCase[dtos.NodeScope, []dtos.Schedule],
+	findScheduleUseCase interfaces.IUseCase[dtos.ScheduleAccessDTO, dtos.Schedule],
+	updateScheduleUseCase interfaces.IUseCase[dtos.ScheduleDTO, dtos.Schedule],
+	deleteScheduleUseCase interfaces.IUseCase[dtos.ScheduleAccessDTO, any],
+	findScheduleRunsUseCase interfaces.IUseCase[dtos.FindScheduleRunsDTO, []dtos.ScheduleRun],
+) scheduleHandler {
+	return scheduleHandler{
+		createScheduleUseCase:   createScheduleUseCase,
+		findSchedulesUseCase:    findSchedulesUseCase,
+		findScheduleUseCase:     findScheduleUseCase,
+		updateScheduleUseCase:   updateScheduleUseCase,
+		deleteScheduleUseCase:   deleteScheduleUseCase,
+		findScheduleRunsUseCase: findScheduleRunsUseCase,
+	}
+}
+
+func (h *scheduleHandler) HandleCreateSchedule(c *gin.Context) {
+	var data dtos.ScheduleDTO
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.Error(errs.Binding(err))
+		return
+	}
+	data.CreatedBy = c.GetString("userId")
+
+	schedule, err := h.createScheduleUseCase.Execute(c.Request.Context(), data)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", schedule.Masked())
+	c.JSON(http.StatusCreated, response)
+}
+
+func (h *scheduleHandler) HandleGetSchedules(c *gin.Context) {
+	schedules, err := h.findSchedulesUseCase.Execute(c.Request.Context(), nodeScope(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	for i := range schedules {
+		schedules[i] = schedules[i].Masked()
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", schedules)
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *scheduleHandler) HandleGetSchedule(c *gin.Context) {
+	schedule, err := h.findScheduleUseCase.Execute(c.Request.Context(), dtos.ScheduleAccessDTO{
+		Scope:      nodeScope(c),
+		ScheduleId: c.Param("id"),
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", schedule.Masked())
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *scheduleHandler) HandleUpdateSchedule(c *gin.Context) {
+	var data dtos.ScheduleDTO
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.Error(errs.Binding(err))
+		return
+	}
+	data.Id = c.Param("id")
+	data.Scope = nodeScope(c)
+
+	schedule, err := h.updateScheduleUseCase.Execute(c.Request.Context(), data)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", schedule.Masked())
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *scheduleHandler) HandleDeleteSchedule(c *gin.Context) {
+	scheduleId := c.Param("id")
+
+	_, err := h.deleteScheduleUseCase.Execute(c.Request.Context(), dtos.ScheduleAccessDTO{
+		Scope:      nodeScope(c),
+		ScheduleId: scheduleId,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", scheduleId)
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *scheduleHandler) HandleGetScheduleRuns(c *gin.Context) {
+	var data dtos.FindScheduleRunsDTO
+	if err := c.ShouldBindQuery(&data); err != nil {
+		c.Error(errs.Binding(err))
+		return
+	}
+	data.ScheduleId = c.Param("id")
+	data.Scope = nodeScope(c)
+
+	runs, err := h.findScheduleRunsUseCase.Execute(c.Request.Context(), data)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", runs)
+	c.JSON(http.StatusOK, response)
+}
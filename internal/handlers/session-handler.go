@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/gin-gonic/gin"
+)
+
+type sessionHandler struct {
+	findSessionsUseCase  interfaces.IUseCase[string, []dtos.Session]
+	revokeSessionUseCase interfaces.IUseCase[dtos.RevokeSessionDTO, any]
+}
+
+func NewSessionHandler(
+	findSessionsUseCase interfaces.IUseCase[string, []dtos.Session],
+	revokeSessionUseCase interfaces.IUseCase[dtos.RevokeSessionDTO, any],
+) sessionHandler {
+	return sessionHandler{
+		findSessionsUseCase:  findSessionsUseCase,
+		revokeSessionUseCase: revokeSessionUseCase,
+	}
+}
+
+func (h *sessionHandler) HandleGetSessions(c *gin.Context) {
+	claims := c.MustGet("claims").(dtos.TokenClaims)
+
+	sessions, err := h.findSessionsUseCase.Execute(c.Request.Context(), claims.UserId)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].Id == claims.SessionId
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", sessions)
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *sessionHandler) HandleDeleteSession(c *gin.Context) {
+	sessionId := c.Param("id")
+
+	_, err := h.revokeSessionUseCase.Execute(c.Request.Context(), dtos.RevokeSessionDTO{
+		SessionId: sessionId,
+		UserId:    c.GetString("userId"),
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", sessionId)
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/JMCDynamics/maestro-server/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+type shellHandler struct {
+	findNodeUseCase          interfaces.IUseCase[string, dtos.Node]
+	openShellSessionUseCase  interfaces.IUseCase[dtos.OpenShellDTO, dtos.ShellSession]
+	findShellSessionsUseCase interfaces.IUseCase[dtos.FindShellSessionsDTO, []dtos.ShellSession]
+	findShellSessionUseCase  interfaces.IUseCase[string, dtos.ShellSession]
+	closeShellSessionUseCase interfaces.IUseCase[string, any]
+
+	upgrader websocket.Upgrader
+}
+
+func NewShellHandler(
+	findNodeUseCase interfaces.IUseCase[string, dtos.Node],
+	openShellSessionUseCase interfaces.IUseCase[dtos.OpenShellDTO, dtos.ShellSession],
+	findShellSessionsUseCase interfaces.IUseCase[dtos.FindShellSessionsDTO, []dtos.ShellSession],
+	findShellSessionUseCase interfaces.IUseCase[string, dtos.ShellSession],
+	closeShellSessionUseCase interfaces.IUseCase[string, any],
+	allowedOrigins []string,
+) shellHandler {
+	return shellHandler{
+		findNodeUseCase:          findNodeUseCase,
+		openShellSessionUseCase:  openShellSessionUseCase,
+		findShellSessionsUseCase: findShellSessionsUseCase,
+		findShellSessionUseCase:  findShellSessionUseCase,
+		closeShellSessionUseCase: closeShellSessionUseCase,
+		upgrader: websocket.Upgrader{
+			CheckOrigin: checkOrigin(allowedOrigins),
+		},
+	}
+}
+
+// checkOrigin accepts WebSocket handshakes from the API's own host and from
+// allowedOrigins, so that other sites cannot open a shell with a user's
+// credentials. A wildcard is ignored here. Clients that are not browsers send
+// no Origin and are accepted.
+func checkOrigin(allowedOrigins []string) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+
+		u, err := url.Parse(origin)
+		if err != nil {
+			return false
+		}
+		if strings.EqualFold(u.Host, r.Host) {
+			return true
+		}
+
+		for _, allowed := range allowedOrigins {
+			if allowed != "*" && strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+func (h *shellHandler) HandleShell(c *gin.Context) {
+	var data dtos.OpenShellDTO
+	if err := c.ShouldBindQuery(&data); err != nil {
+		c.Error(errs.Binding(err))
+		return
+	}
+
+	node, err := h.findNodeUseCase.Execute(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		utils.Logger(c.Request.Context()).Warn().Err(err).Msg("unable to upgrade shell connection")
+		return
+	}
+	defer conn.Close()
+
+	data.Node = node
+	data.UserId = c.GetString("userId")
+	data.Scope = nodeScope(c)
+	data.Conn = conn
+
+	if _, err := h.openShellSessionUseCase.Execute(c.Request.Context(), data); err != nil {
+		conn.WriteJSON(dtos.ShellMessage{Type: dtos.SHELL_CLOSED, Reason: err.Error()})
+	}
+}
+
+func (h *shellHandler) HandleGetShellSessions(c *gin.Context) {
+	var data dtos.FindShellSessionsDTO
+	if err := c.ShouldBindQuery(&data); err != nil {
+		c.Error(errs.Binding(err))
+		return
+	}
+
+	sessions, err := h.findShellSessionsUseCase.Execute(c.Request.Context(), data)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", sessions)
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *shellHandler) HandleCloseShellSession(c *gin.Context) {
+	sessionId := c.Param("id")
+
+	_, err := h.closeShellSessionUseCase.Execute(c.Request.Context(), sessionId)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", sessionId)
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *shellHandler) HandleGetShellRecording(c *gin.Context) {
+	session, err := h.findShellSessionUseCase.Execute(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if _, err := os.Stat(session.RecordingPath); err != nil {
+		c.Error(errs.NotFound("recording not available"))
+		return
+	}
+
+	c.Header("Content-Type", "application/x-asciicast")
+	c.FileAttachment(session.RecordingPath, session.Id+".cast")
+}
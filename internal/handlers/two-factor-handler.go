@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	usecases "github.com/JMCDynamics/maestro-server/internal/use-cases"
+	"github.com/gin-gonic/gin"
+)
+
+type twoFactorHandler struct {
+	enrollTotpUseCase              interfaces.IUseCase[string, dtos.TotpEnrollment]
+	confirmTotpUseCase             interfaces.IUseCase[dtos.TwoFactorCodeDTO, []string]
+	disableTotpUseCase             interfaces.IUseCase[dtos.TwoFactorCodeDTO, any]
+	regenerateRecoveryCodesUseCase interfaces.IUseCase[dtos.TwoFactorCodeDTO, []string]
+	resetTwoFactorUseCase          interfaces.IUseCase[string, any]
+	findAuthPolicyUseCase          interfaces.IUseCase[any, dtos.AuthPolicy]
+	updateAuthPolicyUseCase        interfaces.IUseCase[dtos.AuthPolicy, dtos.AuthPolicy]
+}
+
+func NewTwoFactorHandler(
+	enrollTotpUseCase interfaces.IUseCase[string, dtos.TotpEnrollment],
+	confirmTotpUseCase interfaces.IUseCase[dtos.TwoFactorCodeDTO, []string],
+	disableTotpUseCase interfaces.IUseCase[dtos.TwoFactorCodeDTO, any],
+	regenerateRecoveryCodesUseCase interfaces.IUseCase[dtos.TwoFactorCodeDTO, []string],
+	resetTwoFactorUseCase interfaces.IUseCase[string, any],
+	findAuthPolicyUseCase interfaces.IUseCase[any, dtos.AuthPolicy],
+	updateAuthPolicyUseCase interfaces.IUseCase[dtos.AuthPolicy, dtos.AuthPolicy],
+) twoFactorHandler {
+	return twoFactorHandler{
+		enrollTotpUseCase:              enrollTotpUseCase,
+		confirmTotpUseCase:             confirmTotpUseCase,
+		disableTotpUseCase:             disableTotpUseCase,
+		regenerateRecoveryCodesUseCase: regenerateRecoveryCodesUseCase,
+		resetTwoFactorUseCase:          resetTwoFactorUseCase,
+		findAuthPolicyUseCase:          findAuthPolicyUseCase,
+		updateAuthPolicyUseCase:        updateAuthPolicyUseCase,
+	}
+}
+
+func (h *twoFactorHandler) HandleEnrollTotp(c *gin.Context) {
+	enrollment, err := h.enrollTotpUseCase.Execute(c.Request.Context(), c.GetString("userId"))
+	if err != nil {
+		c.Error(twoFactorError(err))
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", enrollment)
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *twoFactorHandler) HandleConfirmTotp(c *gin.Context) {
+	h.handleCodeAction(c, h.confirmTotpUseCase)
+}
+
+func (h *twoFactorHandler) HandleRegenerateRecoveryCodes(c *gin.Context) {
+	h.handleCodeAction(c, h.regenerateRecoveryCodesUseCase)
+}
+
+func (h *twoFactorHandler) handleCodeAction(c *gin.Context, useCase interfaces.IUseCase[dtos.TwoFactorCodeDTO, []string]) {
+	var data dtos.TwoFactorCodeDTO
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.Error(errs.Binding(err))
+		return
+	}
+	data.UserId = c.GetString("userId")
+
+	recoveryCodes, err := useCase.Execute(c.Request.Context(), data)
+	if err != nil {
+		c.Error(twoFactorError(err))
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", gin.H{"recoveryCodes": recoveryCodes})
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *twoFactorHandler) HandleDisableTotp(c *gin.Context) {
+	var data dtos.TwoFactorCodeDTO
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.Error(errs.Binding(err))
+		return
+	}
+	data.UserId = c.GetString("userId")
+
+	if _, err := h.disableTotpUseCase.Execute(c.Request.Context(), data); err != nil {
+		c.Error(twoFactorError(err))
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", nil)
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *twoFactorHandler) HandleResetTwoFactor(c *gin.Context) {
+	userId := c.Param("id")
+
+	if _, err := h.resetTwoFactorUseCase.Execute(c.Request.Context(), userId); err != nil {
+		c.Error(twoFactorError(err))
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", userId)
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *twoFactorHandler) HandleGetAuthPolicy(c *gin.Context) {
+	policy, err := h.findAuthPolicyUseCase.Execute(c.Request.Context(), nil)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", policy)
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *twoFactorHandler) HandleUpdateAuthPolicy(c *gin.Context) {
+	var data dtos.AuthPolicy
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.Error(errs.Binding(err))
+		return
+	}
+
+	policy, err := h.updateAuthPolicyUseCase.Execute(c.Request.Context(), data)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", policy)
+	c.JSON(http.StatusOK, response)
+}
+
+// errTwoFactorCodeRejected answers a wrong code on these authenticated routes
+// with 403, since clients take 401 for an expired session.
+var errTwoFactorCodeRejected = errs.Forbidden("invalid two-factor code")
+
+func twoFactorError(err error) error {
+	if err == usecases.ErrInvalidTwoFactorCode {
+		return errTwoFactorCodeRejected.WithCause(err)
+	}
+
+	return err
+}
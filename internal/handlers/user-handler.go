@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/gin-gonic/gin"
+)
+
+type userHandler struct {
+	findUsersUseCase      interfaces.IUseCase[any, []dtos.User]
+	findUserUseCase       interfaces.IUseCase[string, dtos.User]
+	createUserUseCase     interfaces.IUseCase[dtos.CreateUserDTO, dtos.User]
+	updateUserUseCase     interfaces.IUseCase[dtos.UpdateUserDTO, dtos.User]
+	deleteUserUseCase     interfaces.IUseCase[dtos.DeleteUserDTO, any]
+	changePasswordUseCase interfaces.IUseCase[dtos.ChangePasswordDTO, any]
+	resetPasswordUseCase  interfaces.IUseCase[dtos.ResetPasswordDTO, any]
+}
+
+func NewUserHandler(
+	findUsersUseCase interfaces.IUseCase[any, []dtos.User],
+	findUserUseCase interfaces.IUseCase[string, dtos.User],
+	createUserUseCase interfaces.IUseCase[dtos.CreateUserDTO, dtos.User],
+	updateUserUseCase interfaces.IUseCase[dtos.UpdateUserDTO, dtos.User],
+	deleteUserUseCase interfaces.IUseCase[dtos.DeleteUserDTO, any],
+	changePasswordUseCase interfaces.IUseCase[dtos.ChangePasswordDTO, any],
+	resetPasswordUseCase interfaces.IUseCase[dtos.ResetPasswordDTO, any],
+) userHandler {
+	return userHandler{
+		findUsersUseCase:      findUsersUseCase,
+		findUserUseCase:       findUserUseCase,
+		createUserUseCase:     createUserUseCase,
+		updateUserUseCase:     updateUserUseCase,
+		deleteUserUseCase:     deleteUserUseCase,
+		changePasswordUseCase: changePasswordUseCase,
+		resetPasswordUseCase:  resetPasswordUseCase,
+	}
+}
+
+func (h *userHandler) HandleGetUsers(c *gin.Context) {
+	users, err := h.findUsersUseCase.Execute(c.Request.Context(), nil)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", users)
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *userHandler) HandleGetUser(c *gin.Context) {
+	user, err := h.findUserUseCase.Execute(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", user)
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *userHandler) HandleCreateUser(c *gin.Context) {
+	var data dtos.CreateUserDTO
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.Error(errs.Binding(err))
+		return
+	}
+
+	user, err := h.createUserUseCase.Execute(c.Request.Context(), data)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", user)
+	c.JSON(http.StatusCreated, response)
+}
+
+func (h *userHandler) HandleUpdateUser(c *gin.Context) {
+	var data dtos.UpdateUserDTO
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.Error(errs.Binding(err))
+		return
+	}
+	data.Id = c.Param("id")
+	data.ActorId = c.GetString("userId")
+
+	user, err := h.updateUserUseCase.Execute(c.Request.Context(), data)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", user)
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *userHandler) HandleDeleteUser(c *gin.Context) {
+	userId := c.Param("id")
+
+	_, err := h.deleteUserUseCase.Execute(c.Request.Context(), dtos.DeleteUserDTO{
+		Id:      userId,
+		ActorId: c.GetString("userId"),
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", userId)
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *userHandler) HandleChangePassword(c *gin.Context) {
+	var data dtos.ChangePasswordDTO
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.Error(errs.Binding(err))
+		return
+	}
+	data.UserId = c.GetString("userId")
+
+	if _, err := h.changePasswordUseCase.Execute(c.Request.Context(), data); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", nil)
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *userHandler) HandleResetPassword(c *gin.Context) {
+	var data dtos.ResetPasswordDTO
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.Error(errs.Binding(err))
+		return
+	}
+	data.UserId = c.Param("id")
+
+	if _, err := h.resetPasswordUseCase.Execute(c.Request.Context(), data); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := dtos.NewDefaultResponse(c.GetString("requestId"), "action exectued with success", nil)
+	c.JSON(http.StatusOK, response)
+}
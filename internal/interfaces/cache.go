@@ -2,12 +2,33 @@ package interfaces
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+// ErrKeyNotFound is returned by Get when the key does not exist.
+var ErrKeyNotFound = errors.New("key not found")
+
 type ICacheGateway interface {
 	Get(ctx context.Context, key string) (string, error)
 	Set(ctx context.Context, key string, value string, expiration time.Duration) error
+	SetIfNotExists(ctx context.Context, key string, value string, expiration time.Duration) (bool, error)
+	// Increment adds one to the counter at key and (re)sets its expiration,
+	// returning the new value. Missing keys start at zero.
+	Increment(ctx context.Context, key string, expiration time.Duration) (int64, error)
+	// Decrement takes one from the counter at key like Increment adds one.
+	Decrement(ctx context.Context, key string, expiration time.Duration) (int64, error)
+	// TTL returns the time left before key expires, or ErrKeyNotFound.
+	TTL(ctx context.Context, key string) (time.Duration, error)
 	Delete(ctx context.Context, key string) error
+	// DeleteIfEquals deletes key only while it still holds value, and
+	// reports whether it did.
+	DeleteIfEquals(ctx context.Context, key string, value string) (bool, error)
+	Ping(ctx context.Context) error
+	// NotifyKeyspaceEvents returns the server's notify-keyspace-events
+	// setting, which must publish expired events for ListenExpiredKeys.
+	NotifyKeyspaceEvents(ctx context.Context) (string, error)
+	// ListenExpiredKeys returns a channel of expired keys, closed by Close.
 	ListenExpiredKeys() <-chan string
+	Close() error
 }
@@ -19,6 +19,10 @@ type IDatabaseGateway interface {
 	QueryRow(ctx context.Context, query string, dest any, args ...any) error
 	Query(ctx context.Context, query string, args ...any) (ResultSet, error)
 	Exec(ctx context.Context, query string, args ...any) error
+	// Transaction runs fn in a transaction joined by every call made with the
+	// ctx fn receives. It commits when fn returns nil.
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+	Ping(ctx context.Context) error
 	Close()
 	RunMigrations() error
 }
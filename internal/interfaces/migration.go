@@ -0,0 +1,16 @@
+package interfaces
+
+import "github.com/JMCDynamics/maestro-server/internal/dtos"
+
+// IMigrationGateway applies schema migrations. Every method holds a database
+// lock while it runs, so concurrent servers migrate one at a time.
+type IMigrationGateway interface {
+	Up() error
+	// Down rolls back the given number of migrations.
+	Down(steps int) error
+	// Force sets the schema version and clears the dirty flag without
+	// running any migration.
+	Force(version int) error
+	Status() (dtos.MigrationStatus, error)
+	Close() error
+}
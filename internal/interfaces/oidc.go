@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+)
+
+type IIdentityProviderGateway interface {
+	// AuthCodeURL returns the provider URL the browser is sent to, bound to
+	// the state, nonce and the S256 challenge of codeVerifier.
+	AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error)
+	// Exchange redeems the code, verifies the ID token and its nonce and
+	// returns the identity it asserts.
+	Exchange(ctx context.Context, code, nonce, codeVerifier string) (dtos.ExternalIdentity, error)
+}
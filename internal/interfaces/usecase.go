@@ -1,5 +1,7 @@
 package interfaces
 
+import "context"
+
 type IUseCase[T any, R any] interface {
-	Execute(props T) (R, error)
-}
\ No newline at end of file
+	Execute(ctx context.Context, props T) (R, error)
+}
@@ -1,8 +1,23 @@
 package interfaces
 
-import "github.com/JMCDynamics/maestro-server/internal/dtos"
+import (
+	"context"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+)
 
 type IVpnGateway interface {
-	GenerateNewPeer(name string) (dtos.ResponseNewPeer, error)
+	// GenerateNewPeer and RemovePeer only honour ctx before they start
+	// changing the interface, so a peer is never left half configured.
+	GenerateNewPeer(ctx context.Context, name string) (dtos.ResponseNewPeer, error)
+	RemovePeer(ctx context.Context, name string) error
 	Run() error
+	// Stop brings the interface down.
+	Stop() error
+	// CheckInterface fails unless the interface exists and is up.
+	CheckInterface() error
+	// InterfaceAddress returns the server's IPv4 address on the interface.
+	InterfaceAddress() (string, error)
+	// CheckConfig fails unless the server configuration can be read.
+	CheckConfig() error
 }
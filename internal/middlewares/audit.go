@@ -0,0 +1,93 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/JMCDynamics/maestro-server/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type auditMiddleware struct {
+	actions                 map[string]string
+	recordAuditEventUseCase interfaces.IUseCase[dtos.AuditEntry, any]
+}
+
+// NewAuditMiddleware audits the routes listed in actions, keyed by
+// "METHOD /full/path" or "* /full/path" for any method, with the action name
+// to record.
+func NewAuditMiddleware(
+	actions map[string]string,
+	recordAuditEventUseCase interfaces.IUseCase[dtos.AuditEntry, any],
+) auditMiddleware {
+	return auditMiddleware{
+		actions:                 actions,
+		recordAuditEventUseCase: recordAuditEventUseCase,
+	}
+}
+
+// Audit must run after RequestId. It runs on every request but only records
+// audited routes. It records once the rest of the chain has finished, so
+// rejected attempts are kept too and the caller set by AuthMiddleware is
+// known.
+func (a *auditMiddleware) Audit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		action, ok := a.actions[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			action, ok = a.actions["* "+c.FullPath()]
+		}
+		if !ok {
+			return
+		}
+		if override := c.GetString("auditAction"); override != "" {
+			action = override
+		}
+
+		target := c.Request.Method + " " + c.Request.URL.Path
+		if path := c.Query("path"); path != "" {
+			target += "?path=" + path
+		}
+
+		entry := dtos.AuditEntry{
+			ActorId:    c.GetString("userId"),
+			ApiKeyId:   c.GetString("apiKeyId"),
+			Action:     action,
+			Target:     target,
+			IpAddress:  c.ClientIP(),
+			RequestId:  c.GetString("requestId"),
+			Outcome:    auditOutcome(c.Writer.Status()),
+			StatusCode: c.Writer.Status(),
+		}
+		if strings.HasPrefix(c.FullPath(), "/nodes/:id") {
+			entry.NodeId = c.Param("id")
+		}
+
+		// record even when the client has already gone away
+		ctx := context.WithoutCancel(c.Request.Context())
+		if _, err := a.recordAuditEventUseCase.Execute(ctx, entry); err != nil {
+			utils.Logger(ctx).Error().Err(err).Str("action", action).Msg("unable to record audit event")
+		}
+	}
+}
+
+// SetAuditAction replaces the route's audit action for this request, for
+// handlers whose sensitivity depends on what they returned.
+func SetAuditAction(c *gin.Context, action string) {
+	c.Set("auditAction", action)
+}
+
+func auditOutcome(status int) dtos.TypeAuditOutcome {
+	switch {
+	case status == http.StatusUnauthorized, status == http.StatusForbidden:
+		return dtos.AUDIT_DENIED
+	case status >= 400:
+		return dtos.AUDIT_FAILURE
+	default:
+		return dtos.AUDIT_SUCCESS
+	}
+}
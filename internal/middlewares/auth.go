@@ -1,20 +1,36 @@
 package middlewares
 
 import (
-	"net/http"
+	"strings"
 
 	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	usecases "github.com/JMCDynamics/maestro-server/internal/use-cases"
+	"github.com/JMCDynamics/maestro-server/internal/utils"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/websocket"
 )
 
+// errApiKeyUserDisabled answers a key whose user is disabled like any other
+// rejected credential.
+var errApiKeyUserDisabled = errs.Unauthorized("user is disabled")
+
 type authMiddleware struct {
-	maestroSecretKey string
+	jwtKeySet                 *utils.JWTKeySet
+	isTokenRevokedUseCase     interfaces.IUseCase[dtos.TokenClaims, bool]
+	authenticateApiKeyUseCase interfaces.IUseCase[string, dtos.ApiKeyPrincipal]
 }
 
-func NewAuthMiddleware(maestroSecretKey string) authMiddleware {
+func NewAuthMiddleware(
+	jwtKeySet *utils.JWTKeySet,
+	isTokenRevokedUseCase interfaces.IUseCase[dtos.TokenClaims, bool],
+	authenticateApiKeyUseCase interfaces.IUseCase[string, dtos.ApiKeyPrincipal],
+) authMiddleware {
 	return authMiddleware{
-		maestroSecretKey: maestroSecretKey,
+		jwtKeySet:                 jwtKeySet,
+		isTokenRevokedUseCase:     isTokenRevokedUseCase,
+		authenticateApiKeyUseCase: authenticateApiKeyUseCase,
 	}
 }
 
@@ -24,40 +40,119 @@ func (a *authMiddleware) AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		bearerToken := c.GetHeader("Authorization")
 
+		// Browsers cannot set headers on WebSocket handshakes or EventSource
+		// requests.
+		streaming := websocket.IsWebSocketUpgrade(c.Request) || c.GetHeader("Accept") == "text/event-stream"
+		if bearerToken == "" && streaming && c.Query("token") != "" {
+			bearerToken = "Bearer " + c.Query("token")
+		}
+
 		if bearerToken == "" {
-			response := dtos.NewDefaultResponse("token not provided", nil)
-			c.JSON(http.StatusUnauthorized, response)
+			c.Error(errs.Unauthorized("token not provided"))
 			c.Abort()
 			return
 		}
 
 		if len(bearerToken) < 7 || bearerToken[:7] != "Bearer " {
-			response := dtos.NewDefaultResponse("invalid token", nil)
-			c.JSON(http.StatusUnauthorized, response)
+			c.Error(errs.Unauthorized("invalid token"))
 			c.Abort()
 			return
 		}
 
 		tokenString := bearerToken[7:]
 
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return []byte(a.maestroSecretKey), nil
-		})
+		if strings.HasPrefix(tokenString, dtos.ApiKeyPrefix) {
+			a.authenticateApiKey(c, tokenString)
+			return
+		}
+
+		claims, err := utils.ParseJWT(tokenString, a.jwtKeySet)
+		if err != nil {
+			c.Error(errs.Unauthorized("invalid or expired token"))
+			c.Abort()
+			return
+		}
+
+		revoked, err := a.isTokenRevokedUseCase.Execute(c.Request.Context(), claims)
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		if revoked {
+			c.Error(errs.Unauthorized("token has been revoked"))
+			c.Abort()
+			return
+		}
+
+		c.Set("claims", claims)
+		c.Set("userId", claims.UserId)
+		c.Set("role", claims.Role)
+		c.Request = c.Request.WithContext(utils.WithUserId(c.Request.Context(), claims.UserId))
+
+		c.Next()
+	}
+}
+
+func (a *authMiddleware) authenticateApiKey(c *gin.Context, key string) {
+	principal, err := a.authenticateApiKeyUseCase.Execute(c.Request.Context(), key)
+	if err != nil {
+		if err == usecases.ErrUserDisabled {
+			err = errApiKeyUserDisabled.WithCause(err)
+		}
+
+		c.Error(err)
+		c.Abort()
+		return
+	}
+
+	c.Set("apiKeyId", principal.ApiKeyId)
+	c.Set("userId", principal.UserId)
+	c.Set("role", principal.Role)
+	c.Set("scopes", principal.Scopes)
+	c.Request = c.Request.WithContext(utils.WithUserId(c.Request.Context(), principal.UserId))
 
-		if err != nil || !token.Valid {
-			response := dtos.NewDefaultResponse("invalid or expired token", nil)
-			c.JSON(http.StatusUnauthorized, response)
+	c.Next()
+}
+
+// RequirePermission must run after AuthMiddleware. It rejects callers whose
+// role, or API key scopes, do not grant the permission with 403.
+func (a *authMiddleware) RequirePermission(permission dtos.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !HasPermission(c, permission) {
+			c.Error(errs.Forbidden("insufficient permissions"))
 			c.Abort()
 			return
 		}
 
-		if claims, ok := token.Claims.(jwt.MapClaims); ok {
-			c.Set("userId", claims["userId"])
+		c.Next()
+	}
+}
+
+// RequireSession must run after AuthMiddleware. It rejects API keys on
+// routes that act on the caller's login session or credentials.
+func (a *authMiddleware) RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get("claims"); !ok {
+			c.Error(errs.Forbidden("this action requires a user session, not an api key"))
+			c.Abort()
+			return
 		}
 
 		c.Next()
 	}
 }
+
+func HasPermission(c *gin.Context, permission dtos.Permission) bool {
+	role, _ := c.Get("role")
+	r, ok := role.(dtos.Role)
+	if !ok {
+		return false
+	}
+
+	scopes, _ := c.Get("scopes")
+	s, _ := scopes.([]dtos.Permission)
+
+	return r.Allows(permission, s)
+}
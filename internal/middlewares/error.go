@@ -0,0 +1,35 @@
+package middlewares
+
+import (
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorHandler answers requests whose handler called c.Error without writing
+// a response. The last error decides the status and code; errors that are
+// not *errs.Error are reported as INTERNAL. Wrapped causes are only logged.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := errs.From(c.Errors.Last().Err)
+		if cause := err.Unwrap(); cause != nil {
+			logger := utils.Logger(c.Request.Context())
+			event := logger.Warn()
+			if err.Code == dtos.INTERNAL {
+				event = logger.Error()
+			}
+			event.Err(cause).
+				Str("path", c.FullPath()).
+				Msg(err.Message)
+		}
+
+		c.JSON(err.Status(), dtos.NewErrorResponse(c.GetString("requestId"), err.Code, err.Message, err.Fields))
+	}
+}
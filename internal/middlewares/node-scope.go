@@ -0,0 +1,47 @@
+package middlewares
+
+import (
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	usecases "github.com/JMCDynamics/maestro-server/internal/use-cases"
+	"github.com/gin-gonic/gin"
+)
+
+type nodeScopeMiddleware struct {
+	canAccessNodeUseCase interfaces.IUseCase[dtos.NodeAccessDTO, bool]
+}
+
+func NewNodeScopeMiddleware(canAccessNodeUseCase interfaces.IUseCase[dtos.NodeAccessDTO, bool]) nodeScopeMiddleware {
+	return nodeScopeMiddleware{
+		canAccessNodeUseCase: canAccessNodeUseCase,
+	}
+}
+
+// RequireNodeAccess must run after AuthMiddleware on routes with an :id node
+// parameter. Nodes outside the caller's groups answer 404, like missing ones.
+func (m *nodeScopeMiddleware) RequireNodeAccess() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, _ := c.Get("role")
+		r, _ := role.(dtos.Role)
+		scopes, _ := c.Get("scopes")
+		s, _ := scopes.([]dtos.Permission)
+
+		allowed, err := m.canAccessNodeUseCase.Execute(c.Request.Context(), dtos.NodeAccessDTO{
+			Scope:  dtos.NewNodeScope(c.GetString("userId"), r, s),
+			NodeId: c.Param("id"),
+		})
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		if !allowed {
+			c.Error(usecases.ErrNodeNotFound)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
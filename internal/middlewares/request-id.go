@@ -0,0 +1,61 @@
+package middlewares
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/JMCDynamics/maestro-server/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/oklog/ulid/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// validRequestId keeps caller-chosen ids short and safe to log and forward.
+var validRequestId = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestId must run right after Tracing. It keeps the caller's X-Request-Id,
+// or generates one, and stores it in the gin and request contexts, where
+// responses, logs, audit entries, spans and node agent requests pick it up.
+func RequestId() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestId := c.GetHeader(utils.RequestIdHeader)
+		if !validRequestId.MatchString(requestId) {
+			requestId = ulid.Make().String()
+		}
+
+		c.Set("requestId", requestId)
+		c.Header(utils.RequestIdHeader, requestId)
+		c.Request = c.Request.WithContext(utils.WithRequestId(c.Request.Context(), requestId))
+		trace.SpanFromContext(c.Request.Context()).SetAttributes(attribute.String("request.id", requestId))
+
+		c.Next()
+	}
+}
+
+// AccessLog logs every request except those to skipPaths once it has been
+// served.
+func AccessLog(skipPaths ...string) gin.HandlerFunc {
+	skip := map[string]bool{}
+	for _, path := range skipPaths {
+		skip[path] = true
+	}
+
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		if skip[c.Request.URL.Path] {
+			return
+		}
+
+		utils.Logger(c.Request.Context()).Info().
+			Str("method", c.Request.Method).
+			Str("path", c.Request.URL.Path).
+			Int("status", c.Writer.Status()).
+			Dur("latency", time.Since(start)).
+			Str("client_ip", c.ClientIP()).
+			Msg("request served")
+	}
+}
@@ -1,6 +1,10 @@
 package server
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -10,56 +14,297 @@ import (
 	"github.com/JMCDynamics/maestro-server/internal/interfaces"
 	"github.com/JMCDynamics/maestro-server/internal/middlewares"
 	"github.com/JMCDynamics/maestro-server/internal/services"
+	"github.com/JMCDynamics/maestro-server/internal/utils"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
 )
 
+// auditedRoutes maps "METHOD /route" ("*" for any method) to the action the
+// audit log records for it.
+var auditedRoutes = map[string]string{
+	"POST /nodes":                       "node.create",
+	"GET /nodes/:id":                    "node.read",
+	"PUT /nodes/:id":                    "node.update",
+	"DELETE /nodes/:id":                 "node.delete",
+	"POST /nodes/broadcast":             "node.broadcast",
+	"GET /nodes/:id/proxy-sse":          "node.proxy",
+	"* /nodes/:id/proxy":                "node.proxy",
+	"POST /nodes/:id/exec":              "node.exec",
+	"GET /nodes/:id/shell":              "node.shell",
+	"GET /nodes/:id/files":              "node.file.download",
+	"PUT /nodes/:id/files":              "node.file.upload",
+	"POST /node-groups":                 "node-group.create",
+	"PUT /node-groups/:id":              "node-group.update",
+	"DELETE /node-groups/:id":           "node-group.delete",
+	"POST /jobs/:id/cancel":             "job.cancel",
+	"GET /shell-sessions/:id/recording": "shell-session.recording.read",
+	"DELETE /shell-sessions/:id":        "shell-session.close",
+	"POST /schedules":                   "schedule.create",
+	"PUT /schedules/:id":                "schedule.update",
+	"DELETE /schedules/:id":             "schedule.delete",
+	"POST /users":                       "user.create",
+	"PUT /users/:id":                    "user.update",
+	"DELETE /users/:id":                 "user.delete",
+	"PUT /users/:id/password":           "user.password.reset",
+	"POST /users/:id/revoke-sessions":   "user.sessions.revoke",
+	"DELETE /users/:id/2fa":             "user.2fa.reset",
+	"DELETE /sessions/:id":              "session.revoke",
+	"POST /api-keys":                    "api-key.create",
+	"DELETE /api-keys/:id":              "api-key.delete",
+	"PUT /auth/policy":                  "auth.policy.update",
+	"PUT /me/password":                  "user.password.change",
+	"POST /me/2fa/totp/confirm":         "user.2fa.enable",
+	"POST /me/2fa/totp/disable":         "user.2fa.disable",
+	"GET /audit":                        "audit.read",
+}
+
 type maestroServer struct {
-	config                  config.Env
-	findNodesUseCase        interfaces.IUseCase[any, []dtos.Node]
-	createNodeUseCase       interfaces.IUseCase[dtos.CreateNodeDTO, dtos.Node]
-	findNodeUseCase         interfaces.IUseCase[string, dtos.Node]
-	authenticateUserUseCase interfaces.IUseCase[dtos.AuthUserDTO, string]
-	setUpNodeUseCase        interfaces.IUseCase[string, any]
-	nodeStatusService       *services.NodeStatusService
-	updateNodeUseCase       interfaces.IUseCase[dtos.UpdateNodeDTO, dtos.Node]
+	config                         config.Env
+	jwtKeySet                      *utils.JWTKeySet
+	listenAddress                  string
+	tlsConfig                      *tls.Config
+	findNodesUseCase               interfaces.IUseCase[dtos.NodeScope, []dtos.Node]
+	createNodeUseCase              interfaces.IUseCase[dtos.CreateNodeDTO, dtos.Node]
+	findNodeUseCase                interfaces.IUseCase[string, dtos.Node]
+	authenticateUserUseCase        interfaces.IUseCase[dtos.AuthUserDTO, dtos.AuthResult]
+	setUpNodeUseCase               interfaces.IUseCase[string, any]
+	nodeStatusService              *services.NodeStatusService
+	updateNodeUseCase              interfaces.IUseCase[dtos.UpdateNodeDTO, dtos.Node]
+	broadcastUseCase               interfaces.IUseCase[dtos.BroadcastDTO, map[string]dtos.BroadcastResult]
+	execCommandUseCase             interfaces.IUseCase[dtos.ExecDTO, dtos.Job]
+	findJobsUseCase                interfaces.IUseCase[dtos.FindJobsDTO, []dtos.Job]
+	findJobUseCase                 interfaces.IUseCase[dtos.JobAccessDTO, dtos.Job]
+	cancelJobUseCase               interfaces.IUseCase[dtos.JobAccessDTO, any]
+	openShellSessionUseCase        interfaces.IUseCase[dtos.OpenShellDTO, dtos.ShellSession]
+	findShellSessionsUseCase       interfaces.IUseCase[dtos.FindShellSessionsDTO, []dtos.ShellSession]
+	findShellSessionUseCase        interfaces.IUseCase[string, dtos.ShellSession]
+	closeShellSessionUseCase       interfaces.IUseCase[string, any]
+	listNodeFilesUseCase           interfaces.IUseCase[dtos.NodeFileDTO, []dtos.FileEntry]
+	statNodeFileUseCase            interfaces.IUseCase[dtos.NodeFileDTO, dtos.FileEntry]
+	uploadNodeFileUseCase          interfaces.IUseCase[dtos.UploadFileDTO, dtos.FileTransfer]
+	downloadNodeFileUseCase        interfaces.IUseCase[dtos.DownloadFileDTO, dtos.FileStream]
+	createScheduleUseCase          interfaces.IUseCase[dtos.ScheduleDTO, dtos.Schedule]
+	findSchedulesUseCase           interfaces.IUseCase[dtos.NodeScope, []dtos.Schedule]
+	findScheduleUseCase            interfaces.IUseCase[dtos.ScheduleAccessDTO, dtos.Schedule]
+	updateScheduleUseCase          interfaces.IUseCase[dtos.ScheduleDTO, dtos.Schedule]
+	deleteScheduleUseCase          interfaces.IUseCase[dtos.ScheduleAccessDTO, any]
+	findScheduleRunsUseCase        interfaces.IUseCase[dtos.FindScheduleRunsDTO, []dtos.ScheduleRun]
+	findUsersUseCase               interfaces.IUseCase[any, []dtos.User]
+	findUserUseCase                interfaces.IUseCase[string, dtos.User]
+	createUserUseCase              interfaces.IUseCase[dtos.CreateUserDTO, dtos.User]
+	updateUserUseCase              interfaces.IUseCase[dtos.UpdateUserDTO, dtos.User]
+	deleteUserUseCase              interfaces.IUseCase[dtos.DeleteUserDTO, any]
+	changePasswordUseCase          interfaces.IUseCase[dtos.ChangePasswordDTO, any]
+	resetPasswordUseCase           interfaces.IUseCase[dtos.ResetPasswordDTO, any]
+	deleteNodeUseCase              interfaces.IUseCase[dtos.NodeAccessDTO, any]
+	canAccessNodeUseCase           interfaces.IUseCase[dtos.NodeAccessDTO, bool]
+	createNodeGroupUseCase         interfaces.IUseCase[dtos.NodeGroupDTO, dtos.NodeGroup]
+	findNodeGroupsUseCase          interfaces.IUseCase[any, []dtos.NodeGroup]
+	findNodeGroupUseCase           interfaces.IUseCase[string, dtos.NodeGroup]
+	updateNodeGroupUseCase         interfaces.IUseCase[dtos.NodeGroupDTO, dtos.NodeGroup]
+	deleteNodeGroupUseCase         interfaces.IUseCase[string, any]
+	logoutUseCase                  interfaces.IUseCase[dtos.TokenClaims, any]
+	revokeUserSessionsUseCase      interfaces.IUseCase[string, any]
+	isTokenRevokedUseCase          interfaces.IUseCase[dtos.TokenClaims, bool]
+	refreshSessionUseCase          interfaces.IUseCase[dtos.RefreshSessionDTO, dtos.TokenPair]
+	findSessionsUseCase            interfaces.IUseCase[string, []dtos.Session]
+	revokeSessionUseCase           interfaces.IUseCase[dtos.RevokeSessionDTO, any]
+	createApiKeyUseCase            interfaces.IUseCase[dtos.CreateApiKeyDTO, dtos.CreatedApiKey]
+	findApiKeysUseCase             interfaces.IUseCase[string, []dtos.ApiKey]
+	deleteApiKeyUseCase            interfaces.IUseCase[dtos.DeleteApiKeyDTO, any]
+	authenticateApiKeyUseCase      interfaces.IUseCase[string, dtos.ApiKeyPrincipal]
+	startOidcLoginUseCase          interfaces.IUseCase[any, dtos.OidcLogin]
+	completeOidcLoginUseCase       interfaces.IUseCase[dtos.OidcCallbackDTO, dtos.TokenPair]
+	verifyTwoFactorUseCase         interfaces.IUseCase[dtos.VerifyTwoFactorDTO, dtos.AuthResult]
+	enrollTotpUseCase              interfaces.IUseCase[string, dtos.TotpEnrollment]
+	confirmTotpUseCase             interfaces.IUseCase[dtos.TwoFactorCodeDTO, []string]
+	disableTotpUseCase             interfaces.IUseCase[dtos.TwoFactorCodeDTO, any]
+	regenerateRecoveryCodesUseCase interfaces.IUseCase[dtos.TwoFactorCodeDTO, []string]
+	resetTwoFactorUseCase          interfaces.IUseCase[string, any]
+	findAuthPolicyUseCase          interfaces.IUseCase[any, dtos.AuthPolicy]
+	updateAuthPolicyUseCase        interfaces.IUseCase[dtos.AuthPolicy, dtos.AuthPolicy]
+	recordAuditEventUseCase        interfaces.IUseCase[dtos.AuditEntry, any]
+	findAuditLogUseCase            interfaces.IUseCase[dtos.FindAuditDTO, dtos.AuditPage]
+	checkReadinessUseCase          interfaces.IUseCase[any, dtos.Readiness]
 }
 
 func NewMaestroServer(
 	config config.Env,
-	findNodesUseCase interfaces.IUseCase[any, []dtos.Node],
+	jwtKeySet *utils.JWTKeySet,
+	listenAddress string,
+	tlsConfig *tls.Config,
+	findNodesUseCase interfaces.IUseCase[dtos.NodeScope, []dtos.Node],
 	createNodeUseCase interfaces.IUseCase[dtos.CreateNodeDTO, dtos.Node],
 	findNodeUseCase interfaces.IUseCase[string, dtos.Node],
-	authenticateUserUseCase interfaces.IUseCase[dtos.AuthUserDTO, string],
+	authenticateUserUseCase interfaces.IUseCase[dtos.AuthUserDTO, dtos.AuthResult],
 	setUpNodeUseCase interfaces.IUseCase[string, any],
 	nodeStatusService *services.NodeStatusService,
 	updateNodeUseCase interfaces.IUseCase[dtos.UpdateNodeDTO, dtos.Node],
+	broadcastUseCase interfaces.IUseCase[dtos.BroadcastDTO, map[string]dtos.BroadcastResult],
+	execCommandUseCase interfaces.IUseCase[dtos.ExecDTO, dtos.Job],
+	findJobsUseCase interfaces.IUseCase[dtos.FindJobsDTO, []dtos.Job],
+	findJobUseCase interfaces.IUseCase[dtos.JobAccessDTO, dtos.Job],
+	cancelJobUseCase interfaces.IUseCase[dtos.JobAccessDTO, any],
+	openShellSessionUseCase interfaces.IUseCase[dtos.OpenShellDTO, dtos.ShellSession],
+	findShellSessionsUseCase interfaces.IUseCase[dtos.FindShellSessionsDTO, []dtos.ShellSession],
+	findShellSessionUseCase interfaces.IUseCase[string, dtos.ShellSession],
+	closeShellSessionUseCase interfaces.IUseCase[string, any],
+	listNodeFilesUseCase interfaces.IUseCase[dtos.NodeFileDTO, []dtos.FileEntry],
+	statNodeFileUseCase interfaces.IUseCase[dtos.NodeFileDTO, dtos.FileEntry],
+	uploadNodeFileUseCase interfaces.IUseCase[dtos.UploadFileDTO, dtos.FileTransfer],
+	downloadNodeFileUseCase interfaces.IUseCase[dtos.DownloadFileDTO, dtos.FileStream],
+	createScheduleUseCase interfaces.IUseCase[dtos.ScheduleDTO, dtos.Schedule],
+	findSchedulesUseCase interfaces.IUseCase[dtos.NodeScope, []dtos.Schedule],
+	findScheduleUseCase interfaces.IUseCase[dtos.ScheduleAccessDTO, dtos.Schedule],
+	updateScheduleUseCase interfaces.IUseCase[dtos.ScheduleDTO, dtos.Schedule],
+	deleteScheduleUseCase interfaces.IUseCase[dtos.ScheduleAccessDTO, any],
+	findScheduleRunsUseCase interfaces.IUseCase[dtos.FindScheduleRunsDTO, []dtos.ScheduleRun],
+	findUsersUseCase interfaces.IUseCase[any, []dtos.User],
+	findUserUseCase interfaces.IUseCase[string, dtos.User],
+	createUserUseCase interfaces.IUseCase[dtos.CreateUserDTO, dtos.User],
+	updateUserUseCase interfaces.IUseCase[dtos.UpdateUserDTO, dtos.User],
+	deleteUserUseCase interfaces.IUseCase[dtos.DeleteUserDTO, any],
+	changePasswordUseCase interfaces.IUseCase[dtos.ChangePasswordDTO, any],
+	resetPasswordUseCase interfaces.IUseCase[dtos.ResetPasswordDTO, any],
+	deleteNodeUseCase interfaces.IUseCase[dtos.NodeAccessDTO, any],
+	canAccessNodeUseCase interfaces.IUseCase[dtos.NodeAccessDTO, bool],
+	createNodeGroupUseCase interfaces.IUseCase[dtos.NodeGroupDTO, dtos.NodeGroup],
+	findNodeGroupsUseCase interfaces.IUseCase[any, []dtos.NodeGroup],
+	findNodeGroupUseCase interfaces.IUseCase[string, dtos.NodeGroup],
+	updateNodeGroupUseCase interfaces.IUseCase[dtos.NodeGroupDTO, dtos.NodeGroup],
+	deleteNodeGroupUseCase interfaces.IUseCase[string, any],
+	logoutUseCase interfaces.IUseCase[dtos.TokenClaims, any],
+	revokeUserSessionsUseCase interfaces.IUseCase[string, any],
+	isTokenRevokedUseCase interfaces.IUseCase[dtos.TokenClaims, bool],
+	refreshSessionUseCase interfaces.IUseCase[dtos.RefreshSessionDTO, dtos.TokenPair],
+	findSessionsUseCase interfaces.IUseCase[string, []dtos.Session],
+	revokeSessionUseCase interfaces.IUseCase[dtos.RevokeSessionDTO, any],
+	createApiKeyUseCase interfaces.IUseCase[dtos.CreateApiKeyDTO, dtos.CreatedApiKey],
+	findApiKeysUseCase interfaces.IUseCase[string, []dtos.ApiKey],
+	deleteApiKeyUseCase interfaces.IUseCase[dtos.DeleteApiKeyDTO, any],
+	authenticateApiKeyUseCase interfaces.IUseCase[string, dtos.ApiKeyPrincipal],
+	startOidcLoginUseCase interfaces.IUseCase[any, dtos.OidcLogin],
+	completeOidcLoginUseCase interfaces.IUseCase[dtos.OidcCallbackDTO, dtos.TokenPair],
+	verifyTwoFactorUseCase interfaces.IUseCase[dtos.VerifyTwoFactorDTO, dtos.AuthResult],
+	enrollTotpUseCase interfaces.IUseCase[string, dtos.TotpEnrollment],
+	confirmTotpUseCase interfaces.IUseCase[dtos.TwoFactorCodeDTO, []string],
+	disableTotpUseCase interfaces.IUseCase[dtos.TwoFactorCodeDTO, any],
+	regenerateRecoveryCodesUseCase interfaces.IUseCase[dtos.TwoFactorCodeDTO, []string],
+	resetTwoFactorUseCase interfaces.IUseCase[string, any],
+	findAuthPolicyUseCase interfaces.IUseCase[any, dtos.AuthPolicy],
+	updateAuthPolicyUseCase interfaces.IUseCase[dtos.AuthPolicy, dtos.AuthPolicy],
+	recordAuditEventUseCase interfaces.IUseCase[dtos.AuditEntry, any],
+	findAuditLogUseCase interfaces.IUseCase[dtos.FindAuditDTO, dtos.AuditPage],
+	checkReadinessUseCase interfaces.IUseCase[any, dtos.Readiness],
 ) *maestroServer {
 	return &maestroServer{
-		config:                  config,
-		findNodesUseCase:        findNodesUseCase,
-		createNodeUseCase:       createNodeUseCase,
-		findNodeUseCase:         findNodeUseCase,
-		authenticateUserUseCase: authenticateUserUseCase,
-		setUpNodeUseCase:        setUpNodeUseCase,
-		nodeStatusService:       nodeStatusService,
-		updateNodeUseCase:       updateNodeUseCase,
+		config:                         config,
+		jwtKeySet:                      jwtKeySet,
+		listenAddress:                  listenAddress,
+		tlsConfig:                      tlsConfig,
+		findNodesUseCase:               findNodesUseCase,
+		createNodeUseCase:              createNodeUseCase,
+		findNodeUseCase:                findNodeUseCase,
+		authenticateUserUseCase:        authenticateUserUseCase,
+		setUpNodeUseCase:               setUpNodeUseCase,
+		nodeStatusService:              nodeStatusService,
+		updateNodeUseCase:              updateNodeUseCase,
+		broadcastUseCase:               broadcastUseCase,
+		execCommandUseCase:             execCommandUseCase,
+		findJobsUseCase:                findJobsUseCase,
+		findJobUseCase:                 findJobUseCase,
+		cancelJobUseCase:               cancelJobUseCase,
+		openShellSessionUseCase:        openShellSessionUseCase,
+		findShellSessionsUseCase:       findShellSessionsUseCase,
+		findShellSessionUseCase:        findShellSessionUseCase,
+		closeShellSessionUseCase:       closeShellSessionUseCase,
+		listNodeFilesUseCase:           listNodeFilesUseCase,
+		statNodeFileUseCase:            statNodeFileUseCase,
+		uploadNodeFileUseCase:          uploadNodeFileUseCase,
+		downloadNodeFileUseCase:        downloadNodeFileUseCase,
+		createScheduleUseCase:          createScheduleUseCase,
+		findSchedulesUseCase:           findSchedulesUseCase,
+		findScheduleUseCase:            findScheduleUseCase,
+		updateScheduleUseCase:          updateScheduleUseCase,
+		deleteScheduleUseCase:          deleteScheduleUseCase,
+		findScheduleRunsUseCase:        findScheduleRunsUseCase,
+		findUsersUseCase:               findUsersUseCase,
+		findUserUseCase:                findUserUseCase,
+		createUserUseCase:              createUserUseCase,
+		updateUserUseCase:              updateUserUseCase,
+		deleteUserUseCase:              deleteUserUseCase,
+		changePasswordUseCase:          changePasswordUseCase,
+		resetPasswordUseCase:           resetPasswordUseCase,
+		deleteNodeUseCase:              deleteNodeUseCase,
+		canAccessNodeUseCase:           canAccessNodeUseCase,
+		createNodeGroupUseCase:         createNodeGroupUseCase,
+		findNodeGroupsUseCase:          findNodeGroupsUseCase,
+		findNodeGroupUseCase:           findNodeGroupUseCase,
+		updateNodeGroupUseCase:         updateNodeGroupUseCase,
+		deleteNodeGroupUseCase:         deleteNodeGroupUseCase,
+		logoutUseCase:                  logoutUseCase,
+		revokeUserSessionsUseCase:      revokeUserSessionsUseCase,
+		isTokenRevokedUseCase:          isTokenRevokedUseCase,
+		refreshSessionUseCase:          refreshSessionUseCase,
+		findSessionsUseCase:            findSessionsUseCase,
+		revokeSessionUseCase:           revokeSessionUseCase,
+		createApiKeyUseCase:            createApiKeyUseCase,
+		findApiKeysUseCase:             findApiKeysUseCase,
+		deleteApiKeyUseCase:            deleteApiKeyUseCase,
+		authenticateApiKeyUseCase:      authenticateApiKeyUseCase,
+		startOidcLoginUseCase:          startOidcLoginUseCase,
+		completeOidcLoginUseCase:       completeOidcLoginUseCase,
+		verifyTwoFactorUseCase:         verifyTwoFactorUseCase,
+		enrollTotpUseCase:              enrollTotpUseCase,
+		confirmTotpUseCase:             confirmTotpUseCase,
+		disableTotpUseCase:             disableTotpUseCase,
+		regenerateRecoveryCodesUseCase: regenerateRecoveryCodesUseCase,
+		resetTwoFactorUseCase:          resetTwoFactorUseCase,
+		findAuthPolicyUseCase:          findAuthPolicyUseCase,
+		updateAuthPolicyUseCase:        updateAuthPolicyUseCase,
+		recordAuditEventUseCase:        recordAuditEventUseCase,
+		findAuditLogUseCase:            findAuditLogUseCase,
+		checkReadinessUseCase:          checkReadinessUseCase,
 	}
 }
 
-func (s *maestroServer) Run() error {
-	r := gin.Default()
+// Run serves the API until ctx is done, then stops accepting connections,
+// ends open event streams and waits up to ShutdownTimeout for in-flight
+// requests before closing the rest.
+func (s *maestroServer) Run(ctx context.Context) error {
+	// health probes hit the server every few seconds, keep them out of the
+	// traces and the access log
+	r := gin.New()
+	if err := r.SetTrustedProxies(s.config.TrustedProxies); err != nil {
+		return fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+	r.Use(
+		middlewares.Tracing(s.config.Tracing.ServiceName, "/healthz", "/readyz"),
+		middlewares.RequestId(),
+		middlewares.AccessLog("/healthz", "/readyz"),
+		gin.Recovery(),
+	)
+	shutdownService := services.NewShutdownService()
 
 	r.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"*"},
-		AllowMethods:     []string{"GET", "PATCH", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowOrigins:     s.config.CorsAllowedOrigins,
+		AllowMethods:     []string{"GET", "HEAD", "PATCH", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Content-Range", "Range", "X-Content-Sha256", "X-Request-Id", "traceparent", "tracestate"},
+		ExposeHeaders:    []string{"Content-Length", "Content-Range", "Accept-Ranges", "X-Content-Sha256", "X-Request-Id"},
 		AllowCredentials: false,
 		MaxAge:           12 * time.Hour,
 	}))
 
-	authMiddleware := middlewares.NewAuthMiddleware(s.config.MaestroSecretKey)
+	authMiddleware := middlewares.NewAuthMiddleware(
+		s.jwtKeySet,
+		s.isTokenRevokedUseCase,
+		s.authenticateApiKeyUseCase,
+	)
+	nodeScopeMiddleware := middlewares.NewNodeScopeMiddleware(s.canAccessNodeUseCase)
+	auditMiddleware := middlewares.NewAuditMiddleware(auditedRoutes, s.recordAuditEventUseCase)
+	r.Use(auditMiddleware.Audit())
+	r.Use(middlewares.ErrorHandler())
 
 	nodeHandler := handlers.NewNodeHandler(
 		s.findNodesUseCase,
@@ -67,30 +312,267 @@ func (s *maestroServer) Run() error {
 		s.findNodeUseCase,
 		s.setUpNodeUseCase,
 		s.nodeStatusService,
+		shutdownService,
 		s.updateNodeUseCase,
+		s.broadcastUseCase,
+		s.deleteNodeUseCase,
+		s.canAccessNodeUseCase,
+	)
+
+	nodeGroupHandler := handlers.NewNodeGroupHandler(
+		s.createNodeGroupUseCase,
+		s.findNodeGroupsUseCase,
+		s.findNodeGroupUseCase,
+		s.updateNodeGroupUseCase,
+		s.deleteNodeGroupUseCase,
+	)
+
+	jobHandler := handlers.NewJobHandler(
+		s.execCommandUseCase,
+		s.findJobsUseCase,
+		s.findJobUseCase,
+		s.cancelJobUseCase,
+		shutdownService,
+	)
+
+	shellHandler := handlers.NewShellHandler(
+		s.findNodeUseCase,
+		s.openShellSessionUseCase,
+		s.findShellSessionsUseCase,
+		s.findShellSessionUseCase,
+		s.closeShellSessionUseCase,
+		s.config.CorsAllowedOrigins,
+	)
+
+	fileHandler := handlers.NewFileHandler(
+		s.config.MaxFileSize,
+		s.listNodeFilesUseCase,
+		s.statNodeFileUseCase,
+		s.uploadNodeFileUseCase,
+		s.downloadNodeFileUseCase,
+	)
+
+	scheduleHandler := handlers.NewScheduleHandler(
+		s.createScheduleUseCase,
+		s.findSchedulesUseCase,
+		s.findScheduleUseCase,
+		s.updateScheduleUseCase,
+		s.deleteScheduleUseCase,
+		s.findScheduleRunsUseCase,
+	)
+
+	userHandler := handlers.NewUserHandler(
+		s.findUsersUseCase,
+		s.findUserUseCase,
+		s.createUserUseCase,
+		s.updateUserUseCase,
+		s.deleteUserUseCase,
+		s.changePasswordUseCase,
+		s.resetPasswordUseCase,
 	)
 
-	authHandler := handlers.NewAuthHandler(s.authenticateUserUseCase)
+	authHandler := handlers.NewAuthHandler(
+		s.authenticateUserUseCase,
+		s.logoutUseCase,
+		s.revokeUserSessionsUseCase,
+		s.refreshSessionUseCase,
+		s.verifyTwoFactorUseCase,
+	)
+
+	healthHandler := handlers.NewHealthHandler(s.checkReadinessUseCase, shutdownService)
+	r.GET("/healthz", healthHandler.HandleHealthz)
+	r.GET("/readyz", healthHandler.HandleReadyz)
+
+	r.GET("/.well-known/jwks.json", func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, s.jwtKeySet.JWKS())
+	})
 	r.POST("/auth", authHandler.HandleAuth)
+	r.POST("/auth/2fa", authHandler.HandleVerifyTwoFactor)
+	r.POST("/auth/refresh", authHandler.HandleRefresh)
+
+	if s.config.OidcEnabled() {
+		oidcHandler := handlers.NewOidcHandler(
+			s.config.OidcPostLoginURL,
+			s.startOidcLoginUseCase,
+			s.completeOidcLoginUseCase,
+		)
+		r.GET("/auth/oidc/login", oidcHandler.HandleLogin)
+		r.GET("/auth/oidc/callback", oidcHandler.HandleCallback)
+	}
+
+	sessionHandler := handlers.NewSessionHandler(
+		s.findSessionsUseCase,
+		s.revokeSessionUseCase,
+	)
+
+	apiKeyHandler := handlers.NewApiKeyHandler(
+		s.createApiKeyUseCase,
+		s.findApiKeysUseCase,
+		s.deleteApiKeyUseCase,
+	)
+
+	twoFactorHandler := handlers.NewTwoFactorHandler(
+		s.enrollTotpUseCase,
+		s.confirmTotpUseCase,
+		s.disableTotpUseCase,
+		s.regenerateRecoveryCodesUseCase,
+		s.resetTwoFactorUseCase,
+		s.findAuthPolicyUseCase,
+		s.updateAuthPolicyUseCase,
+	)
+
+	can := authMiddleware.RequirePermission
+
+	r.GET("/auth/policy", authMiddleware.AuthMiddleware(), can(dtos.PERM_USERS_MANAGE), twoFactorHandler.HandleGetAuthPolicy)
+	r.PUT("/auth/policy", authMiddleware.AuthMiddleware(), can(dtos.PERM_USERS_MANAGE), twoFactorHandler.HandleUpdateAuthPolicy)
 
 	nodeGroups := r.Group("/nodes")
 	{
-		nodeGroups.GET("/events", nodeHandler.HandleListenNodesStatus)
 		nodeGroups.PATCH(":id", nodeHandler.HandleUpdateStatusNode)
-		nodeGroups.PUT(":id", nodeHandler.HandleUpdateNode)
 
 		nodeGroups.Use(authMiddleware.AuthMiddleware())
-		nodeGroups.GET("", nodeHandler.HandleGetNodes)
-		nodeGroups.POST("", nodeHandler.HandleCreateNode)
-		nodeGroups.GET(":id", nodeHandler.HandleGetNode)
-		nodeGroups.GET(":id/proxy-sse", nodeHandler.HandleNodeProxySSE)
-		nodeGroups.Any(":id/proxy", nodeHandler.HandleNodeProxy)
+		nodeGroups.GET("", can(dtos.PERM_NODES_READ), nodeHandler.HandleGetNodes)
+		nodeGroups.POST("", can(dtos.PERM_NODES_WRITE), nodeHandler.HandleCreateNode)
+		nodeGroups.GET("/events", can(dtos.PERM_NODES_READ), nodeHandler.HandleListenNodesStatus)
+		nodeGroups.POST("broadcast", can(dtos.PERM_NODES_PROXY), nodeHandler.HandleBroadcast)
+
+		node := nodeGroups.Group(":id", nodeScopeMiddleware.RequireNodeAccess())
+		node.GET("", can(dtos.PERM_NODES_READ), nodeHandler.HandleGetNode)
+		node.PUT("", can(dtos.PERM_NODES_WRITE), nodeHandler.HandleUpdateNode)
+		node.DELETE("", can(dtos.PERM_NODES_WRITE), nodeHandler.HandleDeleteNode)
+		node.GET("proxy-sse", can(dtos.PERM_NODES_PROXY), nodeHandler.HandleNodeProxySSE)
+		node.Any("proxy", can(dtos.PERM_NODES_PROXY), nodeHandler.HandleNodeProxy)
+		node.POST("exec", can(dtos.PERM_NODES_EXEC), jobHandler.HandleExec)
+		node.GET("shell", can(dtos.PERM_NODES_SHELL), shellHandler.HandleShell)
+		node.GET("files", can(dtos.PERM_FILES_READ), fileHandler.HandleDownloadFile)
+		node.HEAD("files", can(dtos.PERM_FILES_READ), fileHandler.HandleStatFile)
+		node.PUT("files", can(dtos.PERM_FILES_WRITE), fileHandler.HandleUploadFile)
+		node.GET("files/list", can(dtos.PERM_FILES_READ), fileHandler.HandleListFiles)
+	}
+
+	nodeGroupGroups := r.Group("/node-groups")
+	{
+		nodeGroupGroups.Use(authMiddleware.AuthMiddleware(), can(dtos.PERM_NODE_GROUPS))
+		nodeGroupGroups.GET("", nodeGroupHandler.HandleGetNodeGroups)
+		nodeGroupGroups.POST("", nodeGroupHandler.HandleCreateNodeGroup)
+		nodeGroupGroups.GET(":id", nodeGroupHandler.HandleGetNodeGroup)
+		nodeGroupGroups.PUT(":id", nodeGroupHandler.HandleUpdateNodeGroup)
+		nodeGroupGroups.DELETE(":id", nodeGroupHandler.HandleDeleteNodeGroup)
 	}
 
-	r.POST("/logout", authMiddleware.AuthMiddleware(), authHandler.HandleLogout)
+	jobGroups := r.Group("/jobs")
+	{
+		jobGroups.Use(authMiddleware.AuthMiddleware())
+		jobGroups.GET("", can(dtos.PERM_JOBS_READ), jobHandler.HandleGetJobs)
+		jobGroups.GET(":id", can(dtos.PERM_JOBS_READ), jobHandler.HandleGetJob)
+		jobGroups.POST(":id/cancel", can(dtos.PERM_JOBS_CANCEL), jobHandler.HandleCancelJob)
+	}
+
+	shellSessionGroups := r.Group("/shell-sessions")
+	{
+		shellSessionGroups.Use(authMiddleware.AuthMiddleware(), can(dtos.PERM_SHELL_SESSIONS))
+		shellSessionGroups.GET("", shellHandler.HandleGetShellSessions)
+		shellSessionGroups.GET(":id/recording", shellHandler.HandleGetShellRecording)
+		shellSessionGroups.DELETE(":id", shellHandler.HandleCloseShellSession)
+	}
+
+	scheduleGroups := r.Group("/schedules")
+	{
+		scheduleGroups.Use(authMiddleware.AuthMiddleware())
+		scheduleGroups.GET("", can(dtos.PERM_SCHEDULES_READ), scheduleHandler.HandleGetSchedules)
+		scheduleGroups.POST("", can(dtos.PERM_SCHEDULES_WRITE), scheduleHandler.HandleCreateSchedule)
+		scheduleGroups.GET(":id", can(dtos.PERM_SCHEDULES_READ), scheduleHandler.HandleGetSchedule)
+		scheduleGroups.PUT(":id", can(dtos.PERM_SCHEDULES_WRITE), scheduleHandler.HandleUpdateSchedule)
+		scheduleGroups.DELETE(":id", can(dtos.PERM_SCHEDULES_WRITE), scheduleHandler.HandleDeleteSchedule)
+		scheduleGroups.GET(":id/runs", can(dtos.PERM_SCHEDULES_READ), scheduleHandler.HandleGetScheduleRuns)
+	}
+
+	userGroups := r.Group("/users")
+	{
+		userGroups.Use(authMiddleware.AuthMiddleware(), can(dtos.PERM_USERS_MANAGE))
+		userGroups.GET("", userHandler.HandleGetUsers)
+		userGroups.POST("", userHandler.HandleCreateUser)
+		userGroups.GET(":id", userHandler.HandleGetUser)
+		userGroups.PUT(":id", userHandler.HandleUpdateUser)
+		userGroups.DELETE(":id", userHandler.HandleDeleteUser)
+		userGroups.PUT(":id/password", userHandler.HandleResetPassword)
+		userGroups.POST(":id/revoke-sessions", authHandler.HandleRevokeUserSessions)
+		userGroups.DELETE(":id/2fa", twoFactorHandler.HandleResetTwoFactor)
+	}
+
+	sessionGroups := r.Group("/sessions")
+	{
+		sessionGroups.Use(authMiddleware.AuthMiddleware(), authMiddleware.RequireSession())
+		sessionGroups.GET("", sessionHandler.HandleGetSessions)
+		sessionGroups.DELETE(":id", sessionHandler.HandleDeleteSession)
+	}
+
+	apiKeyGroups := r.Group("/api-keys")
+	{
+		apiKeyGroups.Use(authMiddleware.AuthMiddleware(), authMiddleware.RequireSession())
+		apiKeyGroups.GET("", apiKeyHandler.HandleGetApiKeys)
+		apiKeyGroups.POST("", apiKeyHandler.HandleCreateApiKey)
+		apiKeyGroups.DELETE(":id", apiKeyHandler.HandleDeleteApiKey)
+	}
+
+	r.POST("/logout", authMiddleware.AuthMiddleware(), authMiddleware.RequireSession(), authHandler.HandleLogout)
 	r.GET("/me", authMiddleware.AuthMiddleware(), func(ctx *gin.Context) {
 		ctx.JSON(http.StatusOK, "is authenticated")
 	})
+	r.PUT("/me/password", authMiddleware.AuthMiddleware(), authMiddleware.RequireSession(), userHandler.HandleChangePassword)
+
+	auditHandler := handlers.NewAuditHandler(s.findAuditLogUseCase)
+	r.GET("/audit", authMiddleware.AuthMiddleware(), can(dtos.PERM_AUDIT_READ), auditHandler.HandleGetAuditLog)
+
+	twoFactorGroups := r.Group("/me/2fa")
+	{
+		twoFactorGroups.Use(authMiddleware.AuthMiddleware(), authMiddleware.RequireSession())
+		twoFactorGroups.POST("totp", twoFactorHandler.HandleEnrollTotp)
+		twoFactorGroups.POST("totp/confirm", twoFactorHandler.HandleConfirmTotp)
+		twoFactorGroups.POST("totp/disable", twoFactorHandler.HandleDisableTotp)
+		twoFactorGroups.POST("recovery-codes", twoFactorHandler.HandleRegenerateRecoveryCodes)
+	}
+
+	srv := &http.Server{
+		Addr:              s.listenAddress,
+		Handler:           r,
+		TLSConfig:         s.tlsConfig,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		log.Info().Str("address", s.listenAddress).Bool("tls", s.tlsConfig != nil).Msg("listening")
+
+		if s.tlsConfig != nil {
+			// the certificate comes from TLSConfig.GetCertificate
+			errs <- srv.ListenAndServeTLS("", "")
+			return
+		}
+
+		errs <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Info().Dur("timeout", s.config.ShutdownTimeout).Msg("shutting down, draining requests")
+	shutdownService.Shutdown()
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), s.config.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(drainCtx); err != nil {
+		log.Warn().Err(err).Msg("requests still running after shutdown timeout, closing them")
+		srv.Close()
+	}
+
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
 
-	return r.Run(":6276")
+	return nil
 }
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrInterrupted is the cause given to jobs stopped by CancelAll.
+var ErrInterrupted = errors.New("interrupted because the server shut down")
+
+// JobRegistryService tracks the jobs and schedule runs executing on this
+// instance. They outlive the requests that started them, so the server has
+// to stop them itself before it closes its connections.
+type JobRegistryService struct {
+	m sync.Mutex
+
+	running map[string]context.CancelCauseFunc
+	removed chan struct{}
+	closed  bool
+}
+
+func NewJobRegistryService() *JobRegistryService {
+	return &JobRegistryService{
+		running: make(map[string]context.CancelCauseFunc),
+		removed: make(chan struct{}, 1),
+	}
+}
+
+// Register tracks a job until Remove. Jobs registered after CancelAll are
+// cancelled straight away.
+func (s *JobRegistryService) Register(id string, cancel context.CancelCauseFunc) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.closed {
+		cancel(ErrInterrupted)
+	}
+	s.running[id] = cancel
+}
+
+func (s *JobRegistryService) Remove(id string) {
+	s.m.Lock()
+	delete(s.running, id)
+	s.m.Unlock()
+
+	select {
+	case s.removed <- struct{}{}:
+	default:
+	}
+}
+
+// Cancel stops a job running on this instance and reports whether it was found.
+func (s *JobRegistryService) Cancel(id string) bool {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	cancel, ok := s.running[id]
+	if !ok {
+		return false
+	}
+
+	cancel(nil)
+	return true
+}
+
+// CancelAll stops every job running on this instance with ErrInterrupted
+// and waits until they have all been removed, or ctx is done.
+func (s *JobRegistryService) CancelAll(ctx context.Context) error {
+	s.m.Lock()
+	s.closed = true
+	for _, cancel := range s.running {
+		cancel(ErrInterrupted)
+	}
+	s.m.Unlock()
+
+	for {
+		s.m.Lock()
+		running := len(s.running)
+		s.m.Unlock()
+
+		if running == 0 {
+			return nil
+		}
+
+		select {
+		case <-s.removed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
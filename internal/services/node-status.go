@@ -1,21 +1,75 @@
 package services
 
-import "github.com/JMCDynamics/maestro-server/internal/dtos"
+import (
+	"sync"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+)
+
+// nodeStatusBuffer is how many statuses a slow subscriber may fall behind
+// before it starts missing them.
+const nodeStatusBuffer = 64
 
 type NodeStatusService struct {
-	c chan dtos.NodeStatus
+	m sync.Mutex
+
+	subscribers map[chan dtos.NodeStatus]struct{}
+	closed      bool
 }
 
 func NewNodeStatusService() *NodeStatusService {
 	return &NodeStatusService{
-		c: make(chan dtos.NodeStatus),
+		subscribers: make(map[chan dtos.NodeStatus]struct{}),
 	}
 }
 
-func (n *NodeStatusService) ListenStatus() <-chan dtos.NodeStatus {
-	return n.c
+// Subscribe returns a channel that receives every status set from now on,
+// and a function that stops the subscription. The channel is closed when
+// either is called or the service is closed.
+func (n *NodeStatusService) Subscribe() (<-chan dtos.NodeStatus, func()) {
+	n.m.Lock()
+	defer n.m.Unlock()
+
+	c := make(chan dtos.NodeStatus, nodeStatusBuffer)
+	if n.closed {
+		close(c)
+		return c, func() {}
+	}
+	n.subscribers[c] = struct{}{}
+
+	return c, func() {
+		n.m.Lock()
+		defer n.m.Unlock()
+
+		if _, ok := n.subscribers[c]; ok {
+			delete(n.subscribers, c)
+			close(c)
+		}
+	}
 }
 
+// SetStatus hands status to every subscriber. One whose buffer is full misses
+// it rather than holding up the others.
 func (n *NodeStatusService) SetStatus(status dtos.NodeStatus) {
-	n.c <- status
+	n.m.Lock()
+	defer n.m.Unlock()
+
+	for c := range n.subscribers {
+		select {
+		case c <- status:
+		default:
+		}
+	}
+}
+
+// Close ends every subscription.
+func (n *NodeStatusService) Close() {
+	n.m.Lock()
+	defer n.m.Unlock()
+
+	n.closed = true
+	for c := range n.subscribers {
+		delete(n.subscribers, c)
+		close(c)
+	}
 }
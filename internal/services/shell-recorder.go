@@ -0,0 +1,67 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ShellRecorder writes a terminal session in the asciicast v2 format so it can
+// be replayed later with asciinema or any compatible web player.
+type ShellRecorder struct {
+	m sync.Mutex
+
+	file  *os.File
+	start time.Time
+}
+
+func NewShellRecorder(path string, cols, rows int) (*ShellRecorder, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("unable to create recordings folder: %v", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create recording: %v", err)
+	}
+
+	start := time.Now()
+	header, _ := json.Marshal(map[string]any{
+		"version":   2,
+		"width":     cols,
+		"height":    rows,
+		"timestamp": start.Unix(),
+	})
+	if _, err := fmt.Fprintf(file, "%s\n", header); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("unable to write recording header: %v", err)
+	}
+
+	return &ShellRecorder{file: file, start: start}, nil
+}
+
+func (r *ShellRecorder) Output(data string) {
+	r.write("o", data)
+}
+
+func (r *ShellRecorder) Resize(cols, rows int) {
+	r.write("r", fmt.Sprintf("%dx%d", cols, rows))
+}
+
+func (r *ShellRecorder) Close() error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	return r.file.Close()
+}
+
+func (r *ShellRecorder) write(code, data string) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	event, _ := json.Marshal([]any{time.Since(r.start).Seconds(), code, data})
+	fmt.Fprintf(r.file, "%s\n", event)
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"sync"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+)
+
+type activeShellSession struct {
+	session dtos.ShellSession
+	close   func(reason string)
+}
+
+type ShellSessionService struct {
+	m       sync.Mutex
+	running sync.WaitGroup
+
+	sessions map[string]activeShellSession
+}
+
+func NewShellSessionService() *ShellSessionService {
+	return &ShellSessionService{
+		sessions: make(map[string]activeShellSession),
+	}
+}
+
+func (s *ShellSessionService) Register(session dtos.ShellSession, close func(reason string)) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.sessions[session.Id] = activeShellSession{session: session, close: close}
+	s.running.Add(1)
+}
+
+func (s *ShellSessionService) Remove(id string) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if _, ok := s.sessions[id]; ok {
+		delete(s.sessions, id)
+		s.running.Done()
+	}
+}
+
+// Close ends a session running on this instance and reports whether it was found.
+func (s *ShellSessionService) Close(id, reason string) bool {
+	s.m.Lock()
+	active, ok := s.sessions[id]
+	s.m.Unlock()
+
+	if !ok {
+		return false
+	}
+
+	active.close(reason)
+	return true
+}
+
+// CloseAll ends every session running on this instance and waits until
+// they have been recorded as closed, or ctx is done.
+func (s *ShellSessionService) CloseAll(ctx context.Context, reason string) error {
+	s.m.Lock()
+	active := make([]activeShellSession, 0, len(s.sessions))
+	for _, session := range s.sessions {
+		active = append(active, session)
+	}
+	s.m.Unlock()
+
+	for _, session := range active {
+		session.close(reason)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
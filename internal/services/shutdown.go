@@ -0,0 +1,27 @@
+package services
+
+import "sync"
+
+// ShutdownService tells long-lived streams that the server is stopping, so
+// they can end cleanly instead of holding up the drain.
+type ShutdownService struct {
+	once sync.Once
+	done chan struct{}
+}
+
+func NewShutdownService() *ShutdownService {
+	return &ShutdownService{
+		done: make(chan struct{}),
+	}
+}
+
+// Done is closed once Shutdown has been called.
+func (s *ShutdownService) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *ShutdownService) Shutdown() {
+	s.once.Do(func() {
+		close(s.done)
+	})
+}
@@ -0,0 +1,76 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+// apiKeyLastUsedResolution limits last_used_at writes to one per key per
+// minute instead of one per request.
+const apiKeyLastUsedResolution = time.Minute
+
+type AuthenticateApiKeyUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+}
+
+func NewAuthenticateApiKeyUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+) interfaces.IUseCase[string, dtos.ApiKeyPrincipal] {
+	return &AuthenticateApiKeyUseCase{
+		databaseGateway: databaseGateway,
+	}
+}
+
+func (u *AuthenticateApiKeyUseCase) Execute(ctx context.Context, key string) (dtos.ApiKeyPrincipal, error) {
+	sql := `SELECT k.id, k.user_id, k.scopes, k.expires_at, k.last_used_at, u.role, u.disabled
+		FROM api_keys k
+		JOIN users u ON u.id = k.user_id
+		WHERE k.key_hash = $1`
+	resultSet, err := u.databaseGateway.Query(ctx, sql, hashToken(key))
+	if err != nil {
+		return dtos.ApiKeyPrincipal{}, errors.New("unable to find api key")
+	}
+
+	if !resultSet.Next() {
+		resultSet.Close()
+		return dtos.ApiKeyPrincipal{}, ErrInvalidApiKey
+	}
+
+	var (
+		principal  dtos.ApiKeyPrincipal
+		expiresAt  *time.Time
+		lastUsedAt *time.Time
+		disabled   bool
+	)
+	err = resultSet.Scan(&principal.ApiKeyId, &principal.UserId, &principal.Scopes, &expiresAt, &lastUsedAt, &principal.Role, &disabled)
+	resultSet.Close()
+	if err != nil {
+		return dtos.ApiKeyPrincipal{}, fmt.Errorf("failed to scan api key: %w", err)
+	}
+
+	if expiresAt != nil && time.Now().After(*expiresAt) {
+		return dtos.ApiKeyPrincipal{}, ErrInvalidApiKey
+	}
+
+	if disabled {
+		return dtos.ApiKeyPrincipal{}, ErrUserDisabled
+	}
+
+	if len(principal.Scopes) == 0 {
+		principal.Scopes = nil
+	}
+
+	if lastUsedAt == nil || time.Since(*lastUsedAt) > apiKeyLastUsedResolution {
+		sql = "UPDATE api_keys SET last_used_at = now() WHERE id = $1"
+		if err := u.databaseGateway.Exec(ctx, sql, principal.ApiKeyId); err != nil {
+			return dtos.ApiKeyPrincipal{}, fmt.Errorf("unable to record api key use: %v", err)
+		}
+	}
+
+	return principal, nil
+}
@@ -2,61 +2,152 @@ package usecases
 
 import (
 	"context"
-	"errors"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
 	"github.com/JMCDynamics/maestro-server/internal/interfaces"
-	"github.com/JMCDynamics/maestro-server/internal/utils"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthenticateUserUseCase struct {
-	databaseGateway  interfaces.IDatabaseGateway
-	maestroSecretKey string
+	databaseGateway      interfaces.IDatabaseGateway
+	cacheGateway         interfaces.ICacheGateway
+	createSessionUseCase interfaces.IUseCase[dtos.CreateSessionDTO, dtos.TokenPair]
+	totpIssuer           string
 }
 
 var (
-	ErrInvalidCredentials error = errors.New("invalid username or password")
+	ErrInvalidCredentials error = errs.Unauthorized("invalid username or password")
+	ErrUserDisabled       error = errs.Forbidden("user is disabled")
 )
 
 func NewAuthenticateUserUseCase(
 	databaseGateway interfaces.IDatabaseGateway,
-	maestroSecretKey string,
-) interfaces.IUseCase[dtos.AuthUserDTO, string] {
+	cacheGateway interfaces.ICacheGateway,
+	createSessionUseCase interfaces.IUseCase[dtos.CreateSessionDTO, dtos.TokenPair],
+	totpIssuer string,
+) interfaces.IUseCase[dtos.AuthUserDTO, dtos.AuthResult] {
 	return &AuthenticateUserUseCase{
-		databaseGateway:  databaseGateway,
-		maestroSecretKey: maestroSecretKey,
+		databaseGateway:      databaseGateway,
+		cacheGateway:         cacheGateway,
+		createSessionUseCase: createSessionUseCase,
+		totpIssuer:           totpIssuer,
 	}
 }
 
-func (u *AuthenticateUserUseCase) Execute(data dtos.AuthUserDTO) (string, error) {
-	sql := "SELECT id, password FROM users WHERE username = $1 LIMIT 1"
-	result, err := u.databaseGateway.Query(context.Background(), sql, data.Username)
+// Execute checks the password. Users with two-factor authentication, or
+// without it while the policy requires it, get a challenge to complete
+// through VerifyTwoFactorUseCase instead of tokens.
+func (u *AuthenticateUserUseCase) Execute(ctx context.Context, data dtos.AuthUserDTO) (dtos.AuthResult, error) {
+	sql := "SELECT id, password, role, disabled, totp_enabled FROM users WHERE username = $1 LIMIT 1"
+	result, err := u.databaseGateway.Query(ctx, sql, data.Username)
 	if err != nil {
-		return "", err
+		return dtos.AuthResult{}, err
 	}
 	if !result.Next() {
-		return "", ErrInvalidCredentials
+		result.Close()
+		return dtos.AuthResult{}, ErrInvalidCredentials
 	}
 
 	var id string
 	var hashedPassword string
-	if err := result.Scan(&id, &hashedPassword); err != nil {
-		return "", err
+	var role string
+	var disabled bool
+	var totpEnabled bool
+	err = result.Scan(&id, &hashedPassword, &role, &disabled, &totpEnabled)
+	result.Close()
+	if err != nil {
+		return dtos.AuthResult{}, err
 	}
 
 	isValid := checkPasswordHash(data.Password, hashedPassword)
 	if !isValid {
-		return "", ErrInvalidCredentials
+		return dtos.AuthResult{}, ErrInvalidCredentials
+	}
+
+	if disabled {
+		return dtos.AuthResult{}, ErrUserDisabled
+	}
+
+	challengeState := dtos.TwoFactorChallengeState{
+		UserId:    id,
+		Role:      dtos.Role(role),
+		UserAgent: data.UserAgent,
+		IpAddress: data.IpAddress,
+		ExpiresAt: time.Now().Add(twoFactorChallengeTTL),
+	}
+
+	if totpEnabled {
+		return u.challenge(ctx, challengeState, nil)
+	}
+
+	policy, err := findAuthPolicy(ctx, u.databaseGateway)
+	if err != nil {
+		return dtos.AuthResult{}, err
+	}
+
+	if policy.RequireTwoFactor {
+		enrollment, err := startTotpEnrollment(ctx, u.databaseGateway, u.totpIssuer, id, data.Username)
+		if err != nil {
+			return dtos.AuthResult{}, err
+		}
+
+		challengeState.EnrollmentRequired = true
+		return u.challenge(ctx, challengeState, &enrollment)
+	}
+
+	if err := recordLogin(ctx, u.databaseGateway, id); err != nil {
+		return dtos.AuthResult{}, err
 	}
 
-	jwtToken, err := utils.GenerateJWT(id, u.maestroSecretKey)
+	tokens, err := u.createSessionUseCase.Execute(ctx, dtos.CreateSessionDTO{
+		UserId:    id,
+		Role:      dtos.Role(role),
+		UserAgent: data.UserAgent,
+		IpAddress: data.IpAddress,
+	})
 	if err != nil {
-		return "", fmt.Errorf("unable to generate token")
+		return dtos.AuthResult{}, err
+	}
+
+	return dtos.AuthResult{TokenPair: &tokens}, nil
+}
+
+func (u *AuthenticateUserUseCase) challenge(ctx context.Context, state dtos.TwoFactorChallengeState, enrollment *dtos.TotpEnrollment) (dtos.AuthResult, error) {
+	token, err := randomToken()
+	if err != nil {
+		return dtos.AuthResult{}, fmt.Errorf("unable to generate challenge: %v", err)
+	}
+
+	value, err := json.Marshal(state)
+	if err != nil {
+		return dtos.AuthResult{}, err
+	}
+
+	if err := u.cacheGateway.Set(ctx, twoFactorChallengeKey(token), string(value), twoFactorChallengeTTL); err != nil {
+		return dtos.AuthResult{}, fmt.Errorf("unable to store challenge: %v", err)
+	}
+
+	return dtos.AuthResult{
+		Challenge: &dtos.AuthChallenge{
+			Token:              token,
+			ExpiresAt:          state.ExpiresAt,
+			EnrollmentRequired: state.EnrollmentRequired,
+			Enrollment:         enrollment,
+		},
+	}, nil
+}
+
+func recordLogin(ctx context.Context, databaseGateway interfaces.IDatabaseGateway, userId string) error {
+	sql := "UPDATE users SET last_login_at = now() WHERE id = $1"
+	if err := databaseGateway.Exec(ctx, sql, userId); err != nil {
+		return fmt.Errorf("unable to record login: %v", err)
 	}
 
-	return jwtToken, nil
+	return nil
 }
 
 func checkPasswordHash(password, hash string) bool {
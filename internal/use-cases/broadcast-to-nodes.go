@@ -0,0 +1,156 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/JMCDynamics/maestro-server/internal/utils"
+)
+
+const (
+	defaultBroadcastConcurrency = 10
+	defaultBroadcastTimeout     = 30 * time.Second
+	maxBroadcastResponseBytes   = 1 << 20
+)
+
+type BroadcastToNodesUseCase struct {
+	findNodesUseCase interfaces.IUseCase[dtos.NodeScope, []dtos.Node]
+	client           *http.Client
+}
+
+func NewBroadcastToNodesUseCase(
+	findNodesUseCase interfaces.IUseCase[dtos.NodeScope, []dtos.Node],
+) interfaces.IUseCase[dtos.BroadcastDTO, map[string]dtos.BroadcastResult] {
+	return &BroadcastToNodesUseCase{
+		findNodesUseCase: findNodesUseCase,
+		client:           utils.NewNodeAgentClient(0),
+	}
+}
+
+func (u *BroadcastToNodesUseCase) Execute(ctx context.Context, data dtos.BroadcastDTO) (map[string]dtos.BroadcastResult, error) {
+	if err := authorize(data.Scope, dtos.PERM_NODES_PROXY); err != nil {
+		return nil, err
+	}
+
+	nodes, err := u.findNodesUseCase.Execute(ctx, data.Scope)
+	if err != nil {
+		return nil, err
+	}
+
+	concurrency := data.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultBroadcastConcurrency
+	}
+
+	timeout := defaultBroadcastTimeout
+	if data.TimeoutMs > 0 {
+		timeout = time.Duration(data.TimeoutMs) * time.Millisecond
+	}
+
+	var (
+		m       sync.Mutex
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, concurrency)
+		results = map[string]dtos.BroadcastResult{}
+	)
+
+	report := func(result dtos.BroadcastResult) {
+		m.Lock()
+		defer m.Unlock()
+
+		results[result.NodeId] = result
+		if data.OnResult != nil {
+			data.OnResult(result)
+		}
+	}
+
+	for _, node := range nodes {
+		if !data.Selector.Matches(node) {
+			continue
+		}
+
+		// once ctx is done the nodes not contacted yet are reported as
+		// cancelled instead of waiting for a slot
+		acquired := false
+		if ctx.Err() == nil {
+			select {
+			case sem <- struct{}{}:
+				acquired = true
+			case <-ctx.Done():
+			}
+		}
+		if !acquired {
+			report(dtos.BroadcastResult{NodeId: node.Id, Error: context.Cause(ctx).Error()})
+			continue
+		}
+
+		wg.Add(1)
+		go func(node dtos.Node) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			report(u.send(ctx, node, data, timeout))
+		}(node)
+	}
+
+	wg.Wait()
+
+	return results, nil
+}
+
+func (u *BroadcastToNodesUseCase) send(ctx context.Context, node dtos.Node, data dtos.BroadcastDTO, timeout time.Duration) dtos.BroadcastResult {
+	result := dtos.BroadcastResult{NodeId: node.Id}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var body io.Reader
+	if len(data.Body) > 0 {
+		body = bytes.NewReader(data.Body)
+	}
+
+	req, err := utils.NewNodeAgentRequest(ctx, data.Method, utils.NodeAgentURL(node.VpnAddress, data.Path), body)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range data.Headers {
+		req.Header.Set(key, value)
+	}
+
+	start := time.Now()
+	resp, err := u.client.Do(req)
+	if err != nil {
+		result.LatencyMs = time.Since(start).Milliseconds()
+		result.Error = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxBroadcastResponseBytes))
+	result.LatencyMs = time.Since(start).Milliseconds()
+	result.StatusCode = resp.StatusCode
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	if json.Valid(content) {
+		result.Body = content
+	} else {
+		result.Body, _ = json.Marshal(string(content))
+	}
+
+	return result
+}
@@ -0,0 +1,62 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+// scopedNodesFilter restricts a query on nodes to the caller's scope. $1 is
+// NodeScope.All and $2 is NodeScope.UserId.
+var scopedNodesFilter = scopedByNodeFilter("id")
+
+// scopedByNodeFilter restricts a query on rows that belong to the node in
+// column, such as jobs, to the caller's scope, with the same parameters as
+// scopedNodesFilter.
+func scopedByNodeFilter(column string) string {
+	return `($1 OR ` + column + ` IN (
+	SELECT m.node_id FROM node_group_members m
+	JOIN user_node_groups g ON g.group_id = m.group_id
+	WHERE g.user_id = $2))`
+}
+
+// ErrPermissionDenied is returned by use cases acting for a caller whose role,
+// or API key scopes, do not grant the action.
+var ErrPermissionDenied error = errs.Forbidden("insufficient permissions")
+
+// authorize checks the permission in the use case itself, so that it holds
+// for every caller and not only behind the route's middleware.
+func authorize(scope dtos.NodeScope, permission dtos.Permission) error {
+	if !scope.Can(permission) {
+		return ErrPermissionDenied
+	}
+
+	return nil
+}
+
+type CanAccessNodeUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+}
+
+func NewCanAccessNodeUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+) interfaces.IUseCase[dtos.NodeAccessDTO, bool] {
+	return &CanAccessNodeUseCase{
+		databaseGateway: databaseGateway,
+	}
+}
+
+// Execute reports whether the node exists and is within the scope. Callers
+// answer 404 either way so that out-of-scope nodes cannot be discovered.
+func (u *CanAccessNodeUseCase) Execute(ctx context.Context, data dtos.NodeAccessDTO) (bool, error) {
+	var allowed bool
+	sql := "SELECT EXISTS(SELECT 1 FROM nodes WHERE " + scopedNodesFilter + " AND id = $3)"
+	if err := u.databaseGateway.QueryRow(ctx, sql, &allowed, data.Scope.All, data.Scope.UserId, data.NodeId); err != nil {
+		return false, fmt.Errorf("unable to check node access: %v", err)
+	}
+
+	return allowed, nil
+}
@@ -0,0 +1,46 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/JMCDynamics/maestro-server/internal/services"
+)
+
+type CancelJobUseCase struct {
+	findJobUseCase interfaces.IUseCase[dtos.JobAccessDTO, dtos.Job]
+	jobRegistry    *services.JobRegistryService
+}
+
+var (
+	ErrJobNotRunning error = errs.Conflict("job is not running on this server")
+)
+
+func NewCancelJobUseCase(
+	findJobUseCase interfaces.IUseCase[dtos.JobAccessDTO, dtos.Job],
+	jobRegistry *services.JobRegistryService,
+) interfaces.IUseCase[dtos.JobAccessDTO, any] {
+	return &CancelJobUseCase{
+		findJobUseCase: findJobUseCase,
+		jobRegistry:    jobRegistry,
+	}
+}
+
+func (u *CancelJobUseCase) Execute(ctx context.Context, data dtos.JobAccessDTO) (any, error) {
+	if err := authorize(data.Scope, dtos.PERM_JOBS_CANCEL); err != nil {
+		return nil, err
+	}
+
+	job, err := u.findJobUseCase.Execute(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.Status != dtos.JOB_RUNNING || !u.jobRegistry.Cancel(job.Id) {
+		return nil, ErrJobNotRunning
+	}
+
+	return nil, nil
+}
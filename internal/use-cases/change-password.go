@@ -0,0 +1,74 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+type ChangePasswordUseCase struct {
+	databaseGateway           interfaces.IDatabaseGateway
+	revokeUserSessionsUseCase interfaces.IUseCase[string, any]
+}
+
+var (
+	ErrInvalidCurrentPassword error = errs.Forbidden("current password is incorrect")
+)
+
+func NewChangePasswordUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+	revokeUserSessionsUseCase interfaces.IUseCase[string, any],
+) interfaces.IUseCase[dtos.ChangePasswordDTO, any] {
+	return &ChangePasswordUseCase{
+		databaseGateway:           databaseGateway,
+		revokeUserSessionsUseCase: revokeUserSessionsUseCase,
+	}
+}
+
+func (u *ChangePasswordUseCase) Execute(ctx context.Context, data dtos.ChangePasswordDTO) (any, error) {
+	sql := "SELECT password FROM users WHERE id = $1 LIMIT 1"
+	result, err := u.databaseGateway.Query(ctx, sql, data.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !result.Next() {
+		result.Close()
+		return nil, ErrUserNotFound
+	}
+
+	var hashedPassword string
+	err = result.Scan(&hashedPassword)
+	result.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if !checkPasswordHash(data.CurrentPassword, hashedPassword) {
+		return nil, ErrInvalidCurrentPassword
+	}
+
+	if err := setPassword(ctx, u.databaseGateway, data.UserId, data.NewPassword); err != nil {
+		return nil, err
+	}
+
+	// Whoever knew the old password must not stay signed in.
+	return u.revokeUserSessionsUseCase.Execute(ctx, data.UserId)
+}
+
+func setPassword(ctx context.Context, databaseGateway interfaces.IDatabaseGateway, userId, password string) error {
+	passwordHashed, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	sql := "UPDATE users SET password = $1, updated_at = now() WHERE id = $2"
+	if err := databaseGateway.Exec(ctx, sql, passwordHashed, userId); err != nil {
+		return fmt.Errorf("unable to update password: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+const readinessCheckTimeout = 2 * time.Second
+
+type CheckReadinessUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+	cacheGateway    interfaces.ICacheGateway
+	vpnGateway      interfaces.IVpnGateway
+}
+
+func NewCheckReadinessUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+	cacheGateway interfaces.ICacheGateway,
+	vpnGateway interfaces.IVpnGateway,
+) interfaces.IUseCase[any, dtos.Readiness] {
+	return &CheckReadinessUseCase{
+		databaseGateway: databaseGateway,
+		cacheGateway:    cacheGateway,
+		vpnGateway:      vpnGateway,
+	}
+}
+
+// Execute probes every dependency concurrently, each bounded by
+// readinessCheckTimeout. A failed check is reported in the result, not as an
+// error.
+func (u *CheckReadinessUseCase) Execute(ctx context.Context, _ any) (dtos.Readiness, error) {
+	checks := map[string]func(ctx context.Context) error{
+		"postgres":            u.databaseGateway.Ping,
+		"redis":               u.cacheGateway.Ping,
+		"redis-notifications": u.checkExpiredNotifications,
+		"wireguard":           func(context.Context) error { return u.vpnGateway.CheckInterface() },
+		"wireguard-config":    func(context.Context) error { return u.vpnGateway.CheckConfig() },
+	}
+
+	var (
+		m         sync.Mutex
+		wg        sync.WaitGroup
+		readiness = dtos.Readiness{
+			Status: dtos.CHECK_UP,
+			Checks: map[string]dtos.DependencyCheck{},
+		}
+	)
+
+	for name, check := range checks {
+		wg.Add(1)
+
+		go func(name string, check func(ctx context.Context) error) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, readinessCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := check(ctx)
+
+			result := dtos.DependencyCheck{
+				Status:    dtos.CHECK_UP,
+				LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+			}
+			if err != nil {
+				result.Status = dtos.CHECK_DOWN
+				result.Error = err.Error()
+			}
+
+			m.Lock()
+			defer m.Unlock()
+
+			readiness.Checks[name] = result
+			if err != nil {
+				readiness.Status = dtos.CHECK_DOWN
+			}
+		}(name, check)
+	}
+
+	wg.Wait()
+
+	return readiness, nil
+}
+
+// checkExpiredNotifications makes sure Redis publishes the expired key events
+// node heartbeats rely on: E (keyevent channel) together with x or A.
+func (u *CheckReadinessUseCase) checkExpiredNotifications(ctx context.Context) error {
+	flags, err := u.cacheGateway.NotifyKeyspaceEvents(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !strings.Contains(flags, "E") || !strings.ContainsAny(flags, "xA") {
+		return fmt.Errorf("notify-keyspace-events is %q, expired events need at least \"Ex\"", flags)
+	}
+
+	return nil
+}
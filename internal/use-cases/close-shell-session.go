@@ -0,0 +1,33 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/JMCDynamics/maestro-server/internal/services"
+)
+
+type CloseShellSessionUseCase struct {
+	shellSessionService *services.ShellSessionService
+}
+
+var (
+	ErrShellSessionNotActive error = errs.NotFound("shell session is not active on this server")
+)
+
+func NewCloseShellSessionUseCase(
+	shellSessionService *services.ShellSessionService,
+) interfaces.IUseCase[string, any] {
+	return &CloseShellSessionUseCase{
+		shellSessionService: shellSessionService,
+	}
+}
+
+func (u *CloseShellSessionUseCase) Execute(ctx context.Context, id string) (any, error) {
+	if !u.shellSessionService.Close(id, "closed by administrator") {
+		return nil, ErrShellSessionNotActive
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,203 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/oklog/ulid/v2"
+)
+
+var (
+	ErrInvalidOidcState   error = errs.Unauthorized("login state is invalid or expired")
+	ErrOidcUsernameTaken  error = errs.Conflict("a local user already uses this username")
+	ErrOidcRoleNotGranted error = errs.Forbidden("none of your groups grants access to maestro")
+)
+
+// rolePrecedence decides which role wins when several groups match.
+var rolePrecedence = []dtos.Role{dtos.ADMIN, dtos.OPERATOR, dtos.VIEWER}
+
+type CompleteOidcLoginUseCase struct {
+	databaseGateway         interfaces.IDatabaseGateway
+	cacheGateway            interfaces.ICacheGateway
+	identityProviderGateway interfaces.IIdentityProviderGateway
+	createSessionUseCase    interfaces.IUseCase[dtos.CreateSessionDTO, dtos.TokenPair]
+	roleMapping             map[string]dtos.Role
+	defaultRole             dtos.Role
+}
+
+// NewCompleteOidcLoginUseCase maps provider groups to roles with roleMapping.
+// Users matching no group get defaultRole; an empty defaultRole refuses them.
+func NewCompleteOidcLoginUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+	cacheGateway interfaces.ICacheGateway,
+	identityProviderGateway interfaces.IIdentityProviderGateway,
+	createSessionUseCase interfaces.IUseCase[dtos.CreateSessionDTO, dtos.TokenPair],
+	roleMapping map[string]dtos.Role,
+	defaultRole dtos.Role,
+) interfaces.IUseCase[dtos.OidcCallbackDTO, dtos.TokenPair] {
+	return &CompleteOidcLoginUseCase{
+		databaseGateway:         databaseGateway,
+		cacheGateway:            cacheGateway,
+		identityProviderGateway: identityProviderGateway,
+		createSessionUseCase:    createSessionUseCase,
+		roleMapping:             roleMapping,
+		defaultRole:             defaultRole,
+	}
+}
+
+// Execute redeems the provider callback, provisions the user on first login,
+// keeps their role in sync with their groups and starts a Maestro session.
+// Second factors for SSO users are left to the identity provider.
+func (u *CompleteOidcLoginUseCase) Execute(ctx context.Context, data dtos.OidcCallbackDTO) (dtos.TokenPair, error) {
+	key := oidcStateKey(data.State)
+	value, err := u.cacheGateway.Get(ctx, key)
+	if err == interfaces.ErrKeyNotFound {
+		return dtos.TokenPair{}, ErrInvalidOidcState
+	}
+	if err != nil {
+		return dtos.TokenPair{}, fmt.Errorf("unable to read login state: %v", err)
+	}
+
+	// the state is single use: of concurrent callbacks carrying it, only
+	// the one that removes it goes on
+	consumed, err := u.cacheGateway.DeleteIfEquals(ctx, key, value)
+	if err != nil {
+		return dtos.TokenPair{}, fmt.Errorf("unable to consume login state: %v", err)
+	}
+	if !consumed {
+		return dtos.TokenPair{}, ErrInvalidOidcState
+	}
+
+	var loginState dtos.OidcLoginState
+	if err := json.Unmarshal([]byte(value), &loginState); err != nil {
+		return dtos.TokenPair{}, ErrInvalidOidcState
+	}
+
+	identity, err := u.identityProviderGateway.Exchange(ctx, data.Code, loginState.Nonce, loginState.CodeVerifier)
+	if err != nil {
+		return dtos.TokenPair{}, err
+	}
+
+	role, err := u.resolveRole(identity.Groups)
+	if err != nil {
+		return dtos.TokenPair{}, err
+	}
+
+	userId, role, err := u.provision(ctx, identity, role)
+	if err != nil {
+		return dtos.TokenPair{}, err
+	}
+
+	return u.createSessionUseCase.Execute(ctx, dtos.CreateSessionDTO{
+		UserId:    userId,
+		Role:      role,
+		UserAgent: data.UserAgent,
+		IpAddress: data.IpAddress,
+	})
+}
+
+// resolveRole returns the strongest role granted by the groups. Without a
+// mapping every user gets the default role and keeps whatever role an admin
+// gives them later; an empty result means "leave the role alone".
+func (u *CompleteOidcLoginUseCase) resolveRole(groups []string) (dtos.Role, error) {
+	if len(u.roleMapping) == 0 {
+		return "", nil
+	}
+
+	granted := map[dtos.Role]bool{}
+	for _, group := range groups {
+		if role, ok := u.roleMapping[group]; ok {
+			granted[role] = true
+		}
+	}
+
+	for _, role := range rolePrecedence {
+		if granted[role] {
+			return role, nil
+		}
+	}
+
+	if u.defaultRole == "" {
+		return "", ErrOidcRoleNotGranted
+	}
+
+	return u.defaultRole, nil
+}
+
+func (u *CompleteOidcLoginUseCase) provision(ctx context.Context, identity dtos.ExternalIdentity, role dtos.Role) (string, dtos.Role, error) {
+	sql := "SELECT id, role, disabled FROM users WHERE oidc_issuer = $1 AND oidc_subject = $2"
+	resultSet, err := u.databaseGateway.Query(ctx, sql, identity.Issuer, identity.Subject)
+	if err != nil {
+		return "", "", errors.New("unable to find user")
+	}
+
+	var (
+		id          string
+		currentRole dtos.Role
+		disabled    bool
+	)
+	found := resultSet.Next()
+	if found {
+		err = resultSet.Scan(&id, &currentRole, &disabled)
+	}
+	resultSet.Close()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to scan user: %w", err)
+	}
+
+	if found {
+		if disabled {
+			return "", "", ErrUserDisabled
+		}
+
+		if role == "" {
+			role = currentRole
+		}
+
+		sql = "UPDATE users SET role = $1, last_login_at = now() WHERE id = $2"
+		if err := u.databaseGateway.Exec(ctx, sql, role, id); err != nil {
+			return "", "", fmt.Errorf("unable to record login: %v", err)
+		}
+
+		return id, role, nil
+	}
+
+	if role == "" {
+		if u.defaultRole == "" {
+			return "", "", ErrOidcRoleNotGranted
+		}
+		role = u.defaultRole
+	}
+
+	username := identity.Username
+	if username == "" {
+		username = identity.Email
+	}
+	if username == "" {
+		username = identity.Subject
+	}
+
+	taken, err := usernameTaken(ctx, u.databaseGateway, username, "")
+	if err != nil {
+		return "", "", err
+	}
+	if taken {
+		return "", "", ErrOidcUsernameTaken
+	}
+
+	// SSO users have no local password; an empty hash never matches.
+	id = ulid.Make().String()
+	sql = `INSERT INTO users (id, username, password, role, oidc_issuer, oidc_subject, created_at, last_login_at)
+		VALUES($1,$2,'',$3,$4,$5,$6,$6)`
+	if err := u.databaseGateway.Exec(ctx, sql, id, username, role, identity.Issuer, identity.Subject, time.Now()); err != nil {
+		return "", "", fmt.Errorf("unable to provision user: %v", err)
+	}
+
+	return id, role, nil
+}
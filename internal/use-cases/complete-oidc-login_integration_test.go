@@ -0,0 +1,416 @@
+//go:build integration
+
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JMCDynamics/maestro-server/internal/adapters"
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+// These tests run the OIDC login against the mock-oidc service of
+// docker-compose.yml:
+//
+//	docker compose --profile sso up -d mock-oidc
+//	MAESTRO_TEST_OIDC_ISSUER_URL=http://localhost:8080/maestro \
+//		go test -tags integration ./internal/use-cases/
+//
+// Users and sessions are kept in memory; only the identity provider is real.
+
+const testOidcRedirectURL = "http://localhost:6276/auth/oidc/callback"
+
+var testOidcRoleMapping = map[string]dtos.Role{
+	"maestro-admins":    dtos.ADMIN,
+	"maestro-operators": dtos.OPERATOR,
+}
+
+func TestOidcLoginMapsGroupsToRole(t *testing.T) {
+	tests := []struct {
+		name   string
+		groups []string
+		role   dtos.Role
+	}{
+		{name: "strongest mapped group wins", groups: []string{"maestro-operators", "maestro-admins"}, role: dtos.ADMIN},
+		{name: "single mapped group", groups: []string{"maestro-operators"}, role: dtos.OPERATOR},
+		{name: "no mapped group gets the default role", groups: []string{"accounting"}, role: dtos.VIEWER},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			login := newTestOidcLogin(t, dtos.VIEWER)
+
+			data := login.authorize(t, tt.name, tt.groups)
+			if _, err := login.complete.Execute(context.Background(), data); err != nil {
+				t.Fatalf("complete login: %v", err)
+			}
+
+			if login.sessions.last.Role != tt.role {
+				t.Fatalf("session role = %q, want %q", login.sessions.last.Role, tt.role)
+			}
+			if login.database.inserted.role != tt.role {
+				t.Fatalf("provisioned role = %q, want %q", login.database.inserted.role, tt.role)
+			}
+			if login.database.inserted.username != tt.name {
+				t.Fatalf("provisioned username = %q, want %q", login.database.inserted.username, tt.name)
+			}
+		})
+	}
+}
+
+func TestOidcLoginRefusesUnmappedGroupsWithoutDefaultRole(t *testing.T) {
+	login := newTestOidcLogin(t, "")
+
+	data := login.authorize(t, "outsider", []string{"accounting"})
+	_, err := login.complete.Execute(context.Background(), data)
+	if !errors.Is(err, ErrOidcRoleNotGranted) {
+		t.Fatalf("err = %v, want %v", err, ErrOidcRoleNotGranted)
+	}
+}
+
+func TestOidcLoginStateIsSingleUse(t *testing.T) {
+	login := newTestOidcLogin(t, dtos.VIEWER)
+
+	data := login.authorize(t, "replayed", []string{"maestro-admins"})
+	if _, err := login.complete.Execute(context.Background(), data); err != nil {
+		t.Fatalf("complete login: %v", err)
+	}
+
+	_, err := login.complete.Execute(context.Background(), data)
+	if !errors.Is(err, ErrInvalidOidcState) {
+		t.Fatalf("err = %v, want %v", err, ErrInvalidOidcState)
+	}
+}
+
+func TestOidcLoginRejectsConcurrentCallbacks(t *testing.T) {
+	login := newTestOidcLogin(t, dtos.VIEWER)
+
+	data := login.authorize(t, "concurrent", []string{"maestro-admins"})
+
+	const callbacks = 5
+	errs := make(chan error, callbacks)
+	for range callbacks {
+		go func() {
+			_, err := login.complete.Execute(context.Background(), data)
+			errs <- err
+		}()
+	}
+
+	succeeded := 0
+	for range callbacks {
+		err := <-errs
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, ErrInvalidOidcState):
+			t.Fatalf("err = %v, want nil or %v", err, ErrInvalidOidcState)
+		}
+	}
+	if succeeded != 1 {
+		t.Fatalf("%d callbacks succeeded, want 1", succeeded)
+	}
+}
+
+func TestOidcLoginChecksNonce(t *testing.T) {
+	login := newTestOidcLogin(t, dtos.VIEWER)
+
+	data := login.authorize(t, "nonce", []string{"maestro-admins"})
+	login.tamperState(t, data.State, func(state *dtos.OidcLoginState) {
+		state.Nonce = "not-the-nonce-sent-to-the-provider"
+	})
+
+	if _, err := login.complete.Execute(context.Background(), data); err == nil {
+		t.Fatal("login completed with a nonce the provider never saw")
+	}
+	if login.sessions.count != 0 {
+		t.Fatalf("%d sessions created, want 0", login.sessions.count)
+	}
+}
+
+func TestOidcLoginChecksCodeVerifier(t *testing.T) {
+	login := newTestOidcLogin(t, dtos.VIEWER)
+
+	data := login.authorize(t, "pkce", []string{"maestro-admins"})
+	login.tamperState(t, data.State, func(state *dtos.OidcLoginState) {
+		state.CodeVerifier = "not-the-verifier-of-the-challenge-sent-to-the-provider"
+	})
+
+	if _, err := login.complete.Execute(context.Background(), data); err == nil {
+		t.Fatal("login completed with a code verifier that does not match the challenge")
+	}
+	if login.sessions.count != 0 {
+		t.Fatalf("%d sessions created, want 0", login.sessions.count)
+	}
+}
+
+type testOidcLogin struct {
+	cache    *memoryCacheGateway
+	database *memoryUserDatabaseGateway
+	sessions *recordingSessionUseCase
+	start    interfaces.IUseCase[any, dtos.OidcLogin]
+	complete interfaces.IUseCase[dtos.OidcCallbackDTO, dtos.TokenPair]
+}
+
+func newTestOidcLogin(t *testing.T, defaultRole dtos.Role) *testOidcLogin {
+	t.Helper()
+
+	issuerURL := os.Getenv("MAESTRO_TEST_OIDC_ISSUER_URL")
+	if issuerURL == "" {
+		t.Skip("MAESTRO_TEST_OIDC_ISSUER_URL is not set")
+	}
+
+	identityProvider := adapters.NewOidcAdapter(
+		issuerURL,
+		"maestro",
+		"maestro",
+		testOidcRedirectURL,
+		[]string{"openid", "profile", "email", "groups"},
+		"preferred_username",
+		"groups",
+	)
+
+	login := &testOidcLogin{
+		cache:    &memoryCacheGateway{values: map[string]string{}},
+		database: &memoryUserDatabaseGateway{},
+		sessions: &recordingSessionUseCase{},
+	}
+	login.start = NewStartOidcLoginUseCase(login.cache, identityProvider)
+	login.complete = NewCompleteOidcLoginUseCase(
+		login.database,
+		login.cache,
+		identityProvider,
+		login.sessions,
+		testOidcRoleMapping,
+		defaultRole,
+	)
+
+	return login
+}
+
+// authorize starts a login and signs in at the mock provider's interactive
+// login form with the given username and groups, returning the callback the
+// browser would be redirected to.
+func (l *testOidcLogin) authorize(t *testing.T, username string, groups []string) dtos.OidcCallbackDTO {
+	t.Helper()
+
+	start, err := l.start.Execute(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("start login: %v", err)
+	}
+
+	claims, err := json.Marshal(map[string]any{
+		"preferred_username": username,
+		"groups":             groups,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	form := url.Values{"username": {username}, "claims": {string(claims)}}
+	resp, err := client.Post(start.AuthURL, "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+	if err != nil {
+		t.Fatalf("sign in at provider: %v", err)
+	}
+	resp.Body.Close()
+
+	location, err := resp.Location()
+	if err != nil {
+		t.Fatalf("provider did not redirect back (%s): %v", resp.Status, err)
+	}
+	if !strings.HasPrefix(location.String(), testOidcRedirectURL) {
+		t.Fatalf("provider redirected to %s, want %s", location, testOidcRedirectURL)
+	}
+
+	query := location.Query()
+	if query.Get("state") != start.State {
+		t.Fatalf("callback state = %q, want %q", query.Get("state"), start.State)
+	}
+
+	return dtos.OidcCallbackDTO{
+		Code:      query.Get("code"),
+		State:     query.Get("state"),
+		UserAgent: "maestro-test",
+		IpAddress: "127.0.0.1",
+	}
+}
+
+// tamperState rewrites the stored login state, as if the callback were
+// answered with a code issued for another login.
+func (l *testOidcLogin) tamperState(t *testing.T, state string, tamper func(*dtos.OidcLoginState)) {
+	t.Helper()
+
+	key := oidcStateKey(state)
+	value, err := l.cache.Get(context.Background(), key)
+	if err != nil {
+		t.Fatalf("read login state: %v", err)
+	}
+
+	var loginState dtos.OidcLoginState
+	if err := json.Unmarshal([]byte(value), &loginState); err != nil {
+		t.Fatal(err)
+	}
+	tamper(&loginState)
+
+	tampered, err := json.Marshal(loginState)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := l.cache.Set(context.Background(), key, string(tampered), oidcLoginTTL); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// memoryCacheGateway keeps values in memory and ignores expirations.
+type memoryCacheGateway struct {
+	m      sync.Mutex
+	values map[string]string
+}
+
+func (c *memoryCacheGateway) Get(_ context.Context, key string) (string, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	value, ok := c.values[key]
+	if !ok {
+		return "", interfaces.ErrKeyNotFound
+	}
+	return value, nil
+}
+
+func (c *memoryCacheGateway) Set(_ context.Context, key string, value string, _ time.Duration) error {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.values[key] = value
+	return nil
+}
+
+func (c *memoryCacheGateway) SetIfNotExists(_ context.Context, key string, value string, _ time.Duration) (bool, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if _, ok := c.values[key]; ok {
+		return false, nil
+	}
+	c.values[key] = value
+	return true, nil
+}
+
+func (c *memoryCacheGateway) Increment(context.Context, string, time.Duration) (int64, error) {
+	return 0, errors.New("not implemented")
+}
+
+func (c *memoryCacheGateway) Decrement(context.Context, string, time.Duration) (int64, error) {
+	return 0, errors.New("not implemented")
+}
+
+func (c *memoryCacheGateway) TTL(context.Context, string) (time.Duration, error) {
+	return 0, interfaces.ErrKeyNotFound
+}
+
+func (c *memoryCacheGateway) Delete(_ context.Context, key string) error {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	delete(c.values, key)
+	return nil
+}
+
+func (c *memoryCacheGateway) DeleteIfEquals(_ context.Context, key string, value string) (bool, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if current, ok := c.values[key]; !ok || current != value {
+		return false, nil
+	}
+	delete(c.values, key)
+	return true, nil
+}
+
+func (c *memoryCacheGateway) Ping(context.Context) error { return nil }
+
+func (c *memoryCacheGateway) NotifyKeyspaceEvents(context.Context) (string, error) { return "", nil }
+
+func (c *memoryCacheGateway) ListenExpiredKeys() <-chan string { return nil }
+
+func (c *memoryCacheGateway) Close() error { return nil }
+
+// memoryUserDatabaseGateway answers as a database without users and records
+// the user the login provisions.
+type memoryUserDatabaseGateway struct {
+	m        sync.Mutex
+	inserted struct {
+		username string
+		role     dtos.Role
+	}
+}
+
+func (d *memoryUserDatabaseGateway) QueryRow(context.Context, string, any, ...any) error {
+	return errors.New("not implemented")
+}
+
+func (d *memoryUserDatabaseGateway) Query(context.Context, string, ...any) (interfaces.ResultSet, error) {
+	return emptyResultSet{}, nil
+}
+
+func (d *memoryUserDatabaseGateway) Exec(_ context.Context, query string, args ...any) error {
+	if !strings.HasPrefix(query, "INSERT INTO users") {
+		return nil
+	}
+
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	d.inserted.username = args[1].(string)
+	d.inserted.role = args[2].(dtos.Role)
+	return nil
+}
+
+func (d *memoryUserDatabaseGateway) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (d *memoryUserDatabaseGateway) Ping(context.Context) error { return nil }
+
+func (d *memoryUserDatabaseGateway) Close() {}
+
+func (d *memoryUserDatabaseGateway) RunMigrations() error { return nil }
+
+type emptyResultSet struct{}
+
+func (emptyResultSet) Next() bool        { return false }
+func (emptyResultSet) Scan(...any) error { return errors.New("no rows") }
+func (emptyResultSet) Close()            {}
+func (emptyResultSet) Err() error        { return nil }
+
+// recordingSessionUseCase stands in for CreateSessionUseCase.
+type recordingSessionUseCase struct {
+	m     sync.Mutex
+	count int
+	last  dtos.CreateSessionDTO
+}
+
+func (u *recordingSessionUseCase) Execute(_ context.Context, data dtos.CreateSessionDTO) (dtos.TokenPair, error) {
+	u.m.Lock()
+	defer u.m.Unlock()
+
+	u.count++
+	u.last = data
+	return dtos.TokenPair{}, nil
+}
@@ -0,0 +1,48 @@
+package usecases
+
+import (
+	"context"
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+type ConfirmTotpUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+	cacheGateway    interfaces.ICacheGateway
+}
+
+func NewConfirmTotpUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+	cacheGateway interfaces.ICacheGateway,
+) interfaces.IUseCase[dtos.TwoFactorCodeDTO, []string] {
+	return &ConfirmTotpUseCase{
+		databaseGateway: databaseGateway,
+		cacheGateway:    cacheGateway,
+	}
+}
+
+// Execute turns on two-factor authentication once the user proves their app
+// produces valid codes, and returns their recovery codes.
+func (u *ConfirmTotpUseCase) Execute(ctx context.Context, data dtos.TwoFactorCodeDTO) ([]string, error) {
+	state, err := findTotpState(ctx, u.databaseGateway, data.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if state.enabled {
+		return nil, ErrTotpAlreadyEnabled
+	}
+	if state.secret == "" {
+		return nil, ErrTotpNotEnrolled
+	}
+
+	valid, err := verifySecondFactor(ctx, u.databaseGateway, u.cacheGateway, data.UserId, state.secret, data.Code, false)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	return enableTotp(ctx, u.databaseGateway, data.UserId)
+}
@@ -0,0 +1,110 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/oklog/ulid/v2"
+)
+
+const apiKeyColumns = "id, user_id, name, prefix, scopes, expires_at, last_used_at, created_at"
+
+var (
+	ErrScopeNotGranted     error = errs.BadRequest("api key scopes exceed your role's permissions")
+	ErrInvalidApiKeyExpiry error = errs.BadRequest("api key expiry must be in the future")
+	ErrApiKeyNotFound      error = errs.NotFound("api key not found")
+	ErrInvalidApiKey       error = errs.Unauthorized("invalid or expired api key")
+)
+
+type CreateApiKeyUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+}
+
+func NewCreateApiKeyUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+) interfaces.IUseCase[dtos.CreateApiKeyDTO, dtos.CreatedApiKey] {
+	return &CreateApiKeyUseCase{
+		databaseGateway: databaseGateway,
+	}
+}
+
+func (u *CreateApiKeyUseCase) Execute(ctx context.Context, data dtos.CreateApiKeyDTO) (dtos.CreatedApiKey, error) {
+	for _, scope := range data.Scopes {
+		if !data.Role.Can(scope) {
+			return dtos.CreatedApiKey{}, ErrScopeNotGranted
+		}
+	}
+
+	if data.ExpiresAt != nil && !data.ExpiresAt.After(time.Now()) {
+		return dtos.CreatedApiKey{}, ErrInvalidApiKeyExpiry
+	}
+
+	secret, err := randomToken()
+	if err != nil {
+		return dtos.CreatedApiKey{}, fmt.Errorf("unable to generate api key: %v", err)
+	}
+	key := dtos.ApiKeyPrefix + secret
+
+	scopes := compactPermissions(data.Scopes)
+	apiKey := dtos.ApiKey{
+		Id:        ulid.Make().String(),
+		UserId:    data.UserId,
+		Name:      data.Name,
+		Prefix:    key[:len(dtos.ApiKeyPrefix)+8],
+		Scopes:    scopes,
+		ExpiresAt: data.ExpiresAt,
+		CreatedAt: time.Now(),
+	}
+
+	sql := "INSERT INTO api_keys (id, user_id, name, prefix, key_hash, scopes, expires_at, created_at) VALUES($1,$2,$3,$4,$5,$6,$7,$8)"
+	if err := u.databaseGateway.Exec(
+		ctx,
+		sql,
+		apiKey.Id,
+		apiKey.UserId,
+		apiKey.Name,
+		apiKey.Prefix,
+		hashToken(key),
+		apiKey.Scopes,
+		apiKey.ExpiresAt,
+		apiKey.CreatedAt,
+	); err != nil {
+		return dtos.CreatedApiKey{}, fmt.Errorf("unable to create api key: %v", err)
+	}
+
+	return dtos.CreatedApiKey{ApiKey: apiKey, Key: key}, nil
+}
+
+func scanApiKey(resultSet interfaces.ResultSet) (dtos.ApiKey, error) {
+	var apiKey dtos.ApiKey
+	if err := resultSet.Scan(
+		&apiKey.Id,
+		&apiKey.UserId,
+		&apiKey.Name,
+		&apiKey.Prefix,
+		&apiKey.Scopes,
+		&apiKey.ExpiresAt,
+		&apiKey.LastUsedAt,
+		&apiKey.CreatedAt,
+	); err != nil {
+		return dtos.ApiKey{}, fmt.Errorf("failed to scan api key: %w", err)
+	}
+
+	return apiKey, nil
+}
+
+func compactPermissions(permissions []dtos.Permission) []dtos.Permission {
+	compacted := []dtos.Permission{}
+	for _, permission := range permissions {
+		if !slices.Contains(compacted, permission) {
+			compacted = append(compacted, permission)
+		}
+	}
+
+	return compacted
+}
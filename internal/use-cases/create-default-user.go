@@ -30,35 +30,33 @@ func NewCreateDefaultUserUseCase(
 	}
 }
 
-func (u *CreateDefaultUserUseCase) Execute(data dtos.CreateUserDTO) (responseDefaultUser, error) {
+func (u *CreateDefaultUserUseCase) Execute(ctx context.Context, data dtos.CreateUserDTO) (responseDefaultUser, error) {
 	id := ulid.Make().String()
 
-	sql := "SELECT id FROM users WHERE username = $1 LIMIT 1"
-	result, err := u.databaseGateway.Query(context.Background(), sql, data.Username)
+	sql := "SELECT id FROM users LIMIT 1"
+	result, err := u.databaseGateway.Query(ctx, sql)
 	if err != nil {
 		return responseDefaultUser{}, err
 	}
-	if result.Next() {
+	hasUsers := result.Next()
+	result.Close()
+
+	if hasUsers {
 		return responseDefaultUser{
 			AlreadyExists: true,
 		}, nil
 	}
 
-	sql = "DELETE FROM users"
-	if err := u.databaseGateway.Exec(context.Background(), sql); err != nil {
-		return responseDefaultUser{}, fmt.Errorf("unable to ensure only one default user: %v", err)
-	}
-
 	passwordHashed, err := hashPassword(data.Password)
 	if err != nil {
 		return responseDefaultUser{}, err
 	}
-	sql = "INSERT INTO users (id, username, password) VALUES($1,$2,$3)"
-	if err := u.databaseGateway.Exec(context.Background(), sql, id, data.Username, passwordHashed); err != nil {
+	sql = "INSERT INTO users (id, username, password, role) VALUES($1,$2,$3,$4)"
+	if err := u.databaseGateway.Exec(ctx, sql, id, data.Username, passwordHashed, dtos.ADMIN); err != nil {
 		return responseDefaultUser{}, fmt.Errorf("unable to create default user: %v", err)
 	}
 
-	peer, _ := u.vpnGateway.GenerateNewPeer(data.Username)
+	peer, _ := u.vpnGateway.GenerateNewPeer(ctx, data.Username)
 
 	return responseDefaultUser{
 		AlreadyExists: false,
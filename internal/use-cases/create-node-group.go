@@ -0,0 +1,121 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/oklog/ulid/v2"
+)
+
+var (
+	ErrNodeGroupNameTaken     error = errs.Conflict("node group name already in use")
+	ErrUnknownNodeGroupMember error = errs.BadRequest("node group references unknown nodes or users")
+)
+
+type CreateNodeGroupUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+}
+
+func NewCreateNodeGroupUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+) interfaces.IUseCase[dtos.NodeGroupDTO, dtos.NodeGroup] {
+	return &CreateNodeGroupUseCase{
+		databaseGateway: databaseGateway,
+	}
+}
+
+func (u *CreateNodeGroupUseCase) Execute(ctx context.Context, data dtos.NodeGroupDTO) (dtos.NodeGroup, error) {
+	group, err := newNodeGroup(ctx, u.databaseGateway, data)
+	if err != nil {
+		return dtos.NodeGroup{}, err
+	}
+	group.Id = ulid.Make().String()
+	group.CreatedAt = time.Now()
+
+	err = u.databaseGateway.Transaction(ctx, func(ctx context.Context) error {
+		sql := "INSERT INTO node_groups (id, name, description, created_at) VALUES($1,$2,$3,$4)"
+		if err := u.databaseGateway.Exec(ctx, sql, group.Id, group.Name, group.Description, group.CreatedAt); err != nil {
+			return fmt.Errorf("unable to create node group: %v", err)
+		}
+
+		return setNodeGroupMembers(ctx, u.databaseGateway, group)
+	})
+	if err != nil {
+		return dtos.NodeGroup{}, err
+	}
+
+	return group, nil
+}
+
+// newNodeGroup checks the name is free and that every node and user exists,
+// and returns the group with de-duplicated, sorted members.
+func newNodeGroup(ctx context.Context, databaseGateway interfaces.IDatabaseGateway, data dtos.NodeGroupDTO) (dtos.NodeGroup, error) {
+	var taken bool
+	sql := "SELECT EXISTS(SELECT 1 FROM node_groups WHERE name = $1 AND id <> $2)"
+	if err := databaseGateway.QueryRow(ctx, sql, &taken, data.Name, data.Id); err != nil {
+		return dtos.NodeGroup{}, fmt.Errorf("unable to check node group name: %v", err)
+	}
+	if taken {
+		return dtos.NodeGroup{}, ErrNodeGroupNameTaken
+	}
+
+	nodeIds := compactIds(data.NodeIds)
+	userIds := compactIds(data.UserIds)
+
+	for table, ids := range map[string][]string{"nodes": nodeIds, "users": userIds} {
+		var found int
+		sql := "SELECT count(*) FROM " + table + " WHERE id = ANY($1)"
+		if err := databaseGateway.QueryRow(ctx, sql, &found, ids); err != nil {
+			return dtos.NodeGroup{}, fmt.Errorf("unable to check node group members: %v", err)
+		}
+		if found != len(ids) {
+			return dtos.NodeGroup{}, ErrUnknownNodeGroupMember
+		}
+	}
+
+	return dtos.NodeGroup{
+		Id:          data.Id,
+		Name:        data.Name,
+		Description: data.Description,
+		NodeIds:     nodeIds,
+		UserIds:     userIds,
+	}, nil
+}
+
+// setNodeGroupMembers makes the stored members and grants match the group.
+// Run it in the transaction that writes the group, so that readers never see
+// half of the change.
+func setNodeGroupMembers(ctx context.Context, databaseGateway interfaces.IDatabaseGateway, group dtos.NodeGroup) error {
+	statements := []string{
+		"DELETE FROM node_group_members WHERE group_id = $1 AND NOT (node_id = ANY($2))",
+		"INSERT INTO node_group_members (group_id, node_id) SELECT $1, unnest($2::varchar[]) ON CONFLICT DO NOTHING",
+	}
+	for _, sql := range statements {
+		if err := databaseGateway.Exec(ctx, sql, group.Id, group.NodeIds); err != nil {
+			return fmt.Errorf("unable to update node group members: %v", err)
+		}
+	}
+
+	statements = []string{
+		"DELETE FROM user_node_groups WHERE group_id = $1 AND NOT (user_id = ANY($2))",
+		"INSERT INTO user_node_groups (group_id, user_id) SELECT $1, unnest($2::varchar[]) ON CONFLICT DO NOTHING",
+	}
+	for _, sql := range statements {
+		if err := databaseGateway.Exec(ctx, sql, group.Id, group.UserIds); err != nil {
+			return fmt.Errorf("unable to update node group grants: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func compactIds(ids []string) []string {
+	sorted := append([]string{}, ids...)
+	slices.Sort(sorted)
+	return slices.Compact(sorted)
+}
@@ -24,16 +24,25 @@ func NewCreateNode(
 	}
 }
 
-func (u *CreateNode) Execute(data dtos.CreateNodeDTO) (dtos.Node, error) {
+func (u *CreateNode) Execute(ctx context.Context, data dtos.CreateNodeDTO) (dtos.Node, error) {
+	if err := authorize(data.Scope, dtos.PERM_NODES_WRITE); err != nil {
+		return dtos.Node{}, err
+	}
+
 	id := ulid.Make().String()
 
-	config, err := u.vpnGateway.GenerateNewPeer(id)
+	config, err := u.vpnGateway.GenerateNewPeer(ctx, id)
 	if err != nil {
 		return dtos.Node{}, err
 	}
 
-	sql := "INSERT INTO nodes (id, name, vpn_address, operating_system) VALUES($1,$2,$3,$4)"
-	if err := u.databaseGateway.Exec(context.Background(), sql, id, data.Name, config.VpnAddress, data.OperatingSystem); err != nil {
+	labels := data.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	sql := "INSERT INTO nodes (id, name, vpn_address, operating_system, labels) VALUES($1,$2,$3,$4,$5)"
+	if err := u.databaseGateway.Exec(ctx, sql, id, data.Name, config.VpnAddress, data.OperatingSystem, labels); err != nil {
 		return dtos.Node{}, fmt.Errorf("unable to create a node: %v", err)
 	}
 
@@ -43,5 +52,6 @@ func (u *CreateNode) Execute(data dtos.CreateNodeDTO) (dtos.Node, error) {
 		OperatingSystem: data.OperatingSystem,
 		VpnAddress:      config.VpnAddress,
 		Status:          dtos.DOWN,
+		Labels:          labels,
 	}, nil
 }
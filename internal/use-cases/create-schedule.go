@@ -0,0 +1,126 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/oklog/ulid/v2"
+	"github.com/robfig/cron/v3"
+)
+
+var (
+	ErrInvalidScheduleAction error = errs.BadRequest("action does not match the action type")
+)
+
+type CreateScheduleUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+}
+
+func NewCreateScheduleUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+) interfaces.IUseCase[dtos.ScheduleDTO, dtos.Schedule] {
+	return &CreateScheduleUseCase{
+		databaseGateway: databaseGateway,
+	}
+}
+
+// authorizeSchedule requires, besides managing schedules, the permission for
+// the action itself, since runs carry it out later on the creator's behalf.
+func authorizeSchedule(data dtos.ScheduleDTO) error {
+	if err := authorize(data.Scope, dtos.PERM_SCHEDULES_WRITE); err != nil {
+		return err
+	}
+
+	switch data.ActionType {
+	case dtos.SCHEDULE_EXEC:
+		return authorize(data.Scope, dtos.PERM_NODES_EXEC)
+	case dtos.SCHEDULE_PROXY:
+		return authorize(data.Scope, dtos.PERM_NODES_PROXY)
+	}
+
+	return nil
+}
+
+func (u *CreateScheduleUseCase) Execute(ctx context.Context, data dtos.ScheduleDTO) (dtos.Schedule, error) {
+	if err := authorizeSchedule(data); err != nil {
+		return dtos.Schedule{}, err
+	}
+
+	schedule, err := newSchedule(data)
+	if err != nil {
+		return dtos.Schedule{}, err
+	}
+	schedule.Id = ulid.Make().String()
+	schedule.CreatedAt = time.Now()
+
+	sql := `INSERT INTO schedules (id, name, cron_expression, selector, action_type, action, max_retries, retry_delay_ms, enabled, created_by, next_run_at, created_at)
+		VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)`
+	if err := u.databaseGateway.Exec(
+		ctx,
+		sql,
+		schedule.Id,
+		schedule.Name,
+		schedule.CronExpression,
+		schedule.Selector,
+		schedule.ActionType,
+		schedule.Action,
+		schedule.MaxRetries,
+		schedule.RetryDelayMs,
+		schedule.Enabled,
+		schedule.CreatedBy,
+		schedule.NextRunAt,
+		schedule.CreatedAt,
+	); err != nil {
+		return dtos.Schedule{}, fmt.Errorf("unable to create a schedule: %v", err)
+	}
+
+	return schedule, nil
+}
+
+// newSchedule validates the action against its type and computes the first
+// run from now. Disabled schedules have no next run.
+func newSchedule(data dtos.ScheduleDTO) (dtos.Schedule, error) {
+	switch data.ActionType {
+	case dtos.SCHEDULE_PROXY:
+		if data.Action.Method == "" || len(data.Action.Path) == 0 || data.Action.Path[0] != '/' {
+			return dtos.Schedule{}, ErrInvalidScheduleAction
+		}
+	case dtos.SCHEDULE_EXEC:
+		if data.Action.Command == "" {
+			return dtos.Schedule{}, ErrInvalidScheduleAction
+		}
+	default:
+		return dtos.Schedule{}, ErrInvalidScheduleAction
+	}
+
+	cronSchedule, err := cron.ParseStandard(data.CronExpression)
+	if err != nil {
+		return dtos.Schedule{}, fmt.Errorf("invalid cron expression: %v", err)
+	}
+
+	enabled := data.Enabled == nil || *data.Enabled
+
+	var nextRunAt *time.Time
+	if enabled {
+		next := cronSchedule.Next(time.Now())
+		nextRunAt = &next
+	}
+
+	return dtos.Schedule{
+		Id:             data.Id,
+		Name:           data.Name,
+		CronExpression: data.CronExpression,
+		Selector:       data.Selector,
+		ActionType:     data.ActionType,
+		Action:         data.Action,
+		MaxRetries:     data.MaxRetries,
+		RetryDelayMs:   data.RetryDelayMs,
+		Enabled:        enabled,
+		CreatedBy:      data.CreatedBy,
+		NextRunAt:      nextRunAt,
+	}, nil
+}
@@ -0,0 +1,110 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/JMCDynamics/maestro-server/internal/utils"
+	"github.com/oklog/ulid/v2"
+)
+
+type CreateSessionUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+	jwtKeySet       *utils.JWTKeySet
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+}
+
+func NewCreateSessionUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+	jwtKeySet *utils.JWTKeySet,
+	accessTokenTTL time.Duration,
+	refreshTokenTTL time.Duration,
+) interfaces.IUseCase[dtos.CreateSessionDTO, dtos.TokenPair] {
+	return &CreateSessionUseCase{
+		databaseGateway: databaseGateway,
+		jwtKeySet:       jwtKeySet,
+		accessTokenTTL:  accessTokenTTL,
+		refreshTokenTTL: refreshTokenTTL,
+	}
+}
+
+// Execute starts a session for a user who has already proven who they are and
+// returns its first token pair.
+func (u *CreateSessionUseCase) Execute(ctx context.Context, data dtos.CreateSessionDTO) (dtos.TokenPair, error) {
+	// Spent refresh tokens are kept for reuse detection until their session
+	// expires; drop the user's expired sessions along with them.
+	sql := "DELETE FROM sessions WHERE user_id = $1 AND expires_at < now()"
+	if err := u.databaseGateway.Exec(ctx, sql, data.UserId); err != nil {
+		return dtos.TokenPair{}, fmt.Errorf("unable to prune sessions: %v", err)
+	}
+
+	sessionId := ulid.Make().String()
+	expiresAt := time.Now().Add(u.refreshTokenTTL)
+
+	sql = "INSERT INTO sessions (id, user_id, user_agent, ip_address, expires_at) VALUES($1,$2,$3,$4,$5)"
+	if err := u.databaseGateway.Exec(ctx, sql, sessionId, data.UserId, data.UserAgent, data.IpAddress, expiresAt); err != nil {
+		return dtos.TokenPair{}, fmt.Errorf("unable to create session: %v", err)
+	}
+
+	return issueTokenPair(ctx, u.databaseGateway, u.jwtKeySet, u.accessTokenTTL, expiresAt, sessionId, data.UserId, data.Role)
+}
+
+// issueTokenPair stores a new refresh token for the session and signs an
+// access token bound to it.
+func issueTokenPair(
+	ctx context.Context,
+	databaseGateway interfaces.IDatabaseGateway,
+	jwtKeySet *utils.JWTKeySet,
+	accessTokenTTL time.Duration,
+	refreshExpiresAt time.Time,
+	sessionId, userId string,
+	role dtos.Role,
+) (dtos.TokenPair, error) {
+	refreshToken, err := randomToken()
+	if err != nil {
+		return dtos.TokenPair{}, fmt.Errorf("unable to generate refresh token: %v", err)
+	}
+
+	sql := "INSERT INTO refresh_tokens (token_hash, session_id, expires_at) VALUES($1,$2,$3)"
+	if err := databaseGateway.Exec(ctx, sql, hashToken(refreshToken), sessionId, refreshExpiresAt); err != nil {
+		return dtos.TokenPair{}, fmt.Errorf("unable to store refresh token: %v", err)
+	}
+
+	accessExpiresAt := time.Now().Add(accessTokenTTL)
+	accessToken, err := utils.GenerateJWT(userId, string(role), sessionId, jwtKeySet, accessExpiresAt)
+	if err != nil {
+		return dtos.TokenPair{}, fmt.Errorf("unable to generate token")
+	}
+
+	return dtos.TokenPair{
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: accessExpiresAt,
+		RefreshToken:         refreshToken,
+		SessionId:            sessionId,
+	}, nil
+}
+
+// randomToken returns 256 random bits, URL-safe encoded.
+func randomToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// Refresh tokens and API keys are random, so a plain SHA-256 is enough to keep
+// them out of the database.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,73 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/oklog/ulid/v2"
+)
+
+type CreateUserUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+}
+
+var (
+	ErrUsernameTaken error = errs.Conflict("username already in use")
+)
+
+func NewCreateUserUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+) interfaces.IUseCase[dtos.CreateUserDTO, dtos.User] {
+	return &CreateUserUseCase{
+		databaseGateway: databaseGateway,
+	}
+}
+
+func (u *CreateUserUseCase) Execute(ctx context.Context, data dtos.CreateUserDTO) (dtos.User, error) {
+	taken, err := usernameTaken(ctx, u.databaseGateway, data.Username, "")
+	if err != nil {
+		return dtos.User{}, err
+	}
+	if taken {
+		return dtos.User{}, ErrUsernameTaken
+	}
+
+	passwordHashed, err := hashPassword(data.Password)
+	if err != nil {
+		return dtos.User{}, err
+	}
+
+	role := data.Role
+	if role == "" {
+		role = dtos.VIEWER
+	}
+
+	user := dtos.User{
+		Id:        ulid.Make().String(),
+		Username:  data.Username,
+		Role:      role,
+		CreatedAt: time.Now(),
+	}
+
+	sql := "INSERT INTO users (id, username, password, role, created_at) VALUES($1,$2,$3,$4,$5)"
+	if err := u.databaseGateway.Exec(ctx, sql, user.Id, user.Username, passwordHashed, user.Role, user.CreatedAt); err != nil {
+		return dtos.User{}, fmt.Errorf("unable to create user: %v", err)
+	}
+
+	return user, nil
+}
+
+func usernameTaken(ctx context.Context, databaseGateway interfaces.IDatabaseGateway, username, exceptId string) (bool, error) {
+	sql := "SELECT id FROM users WHERE username = $1 AND id <> $2 LIMIT 1"
+	result, err := databaseGateway.Query(ctx, sql, username, exceptId)
+	if err != nil {
+		return false, err
+	}
+	defer result.Close()
+
+	return result.Next(), nil
+}
@@ -0,0 +1,44 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+type DeleteApiKeyUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+}
+
+func NewDeleteApiKeyUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+) interfaces.IUseCase[dtos.DeleteApiKeyDTO, any] {
+	return &DeleteApiKeyUseCase{
+		databaseGateway: databaseGateway,
+	}
+}
+
+// Execute revokes one of the user's own keys. Keys of other users are
+// reported as not found.
+func (u *DeleteApiKeyUseCase) Execute(ctx context.Context, data dtos.DeleteApiKeyDTO) (any, error) {
+	sql := "DELETE FROM api_keys WHERE id = $1 AND user_id = $2 RETURNING id"
+	resultSet, err := u.databaseGateway.Query(ctx, sql, data.Id, data.UserId)
+	if err != nil {
+		return nil, errors.New("unable to delete api key")
+	}
+	deleted := resultSet.Next()
+	resultSet.Close()
+
+	if err := resultSet.Err(); err != nil {
+		return nil, fmt.Errorf("unable to delete api key: %v", err)
+	}
+
+	if !deleted {
+		return nil, ErrApiKeyNotFound
+	}
+
+	return nil, nil
+}
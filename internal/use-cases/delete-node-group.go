@@ -0,0 +1,37 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+type DeleteNodeGroupUseCase struct {
+	databaseGateway      interfaces.IDatabaseGateway
+	findNodeGroupUseCase interfaces.IUseCase[string, dtos.NodeGroup]
+}
+
+func NewDeleteNodeGroupUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+	findNodeGroupUseCase interfaces.IUseCase[string, dtos.NodeGroup],
+) interfaces.IUseCase[string, any] {
+	return &DeleteNodeGroupUseCase{
+		databaseGateway:      databaseGateway,
+		findNodeGroupUseCase: findNodeGroupUseCase,
+	}
+}
+
+func (u *DeleteNodeGroupUseCase) Execute(ctx context.Context, id string) (any, error) {
+	if _, err := u.findNodeGroupUseCase.Execute(ctx, id); err != nil {
+		return nil, err
+	}
+
+	sql := "DELETE FROM node_groups WHERE id = $1"
+	if err := u.databaseGateway.Exec(ctx, sql, id); err != nil {
+		return nil, fmt.Errorf("unable to delete node group: %v", err)
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,53 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+type DeleteNodeUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+	cacheGateway    interfaces.ICacheGateway
+	vpnGateway      interfaces.IVpnGateway
+	findNodeUseCase interfaces.IUseCase[string, dtos.Node]
+}
+
+func NewDeleteNodeUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+	cacheGateway interfaces.ICacheGateway,
+	vpnGateway interfaces.IVpnGateway,
+	findNodeUseCase interfaces.IUseCase[string, dtos.Node],
+) interfaces.IUseCase[dtos.NodeAccessDTO, any] {
+	return &DeleteNodeUseCase{
+		databaseGateway: databaseGateway,
+		cacheGateway:    cacheGateway,
+		vpnGateway:      vpnGateway,
+		findNodeUseCase: findNodeUseCase,
+	}
+}
+
+func (u *DeleteNodeUseCase) Execute(ctx context.Context, data dtos.NodeAccessDTO) (any, error) {
+	if err := authorize(data.Scope, dtos.PERM_NODES_WRITE); err != nil {
+		return nil, err
+	}
+
+	if _, err := u.findNodeUseCase.Execute(ctx, data.NodeId); err != nil {
+		return nil, err
+	}
+
+	if err := u.vpnGateway.RemovePeer(ctx, data.NodeId); err != nil {
+		return nil, err
+	}
+
+	sql := "DELETE FROM nodes WHERE id = $1"
+	if err := u.databaseGateway.Exec(ctx, sql, data.NodeId); err != nil {
+		return nil, fmt.Errorf("unable to delete node: %v", err)
+	}
+
+	u.cacheGateway.Delete(ctx, data.NodeId)
+
+	return nil, nil
+}
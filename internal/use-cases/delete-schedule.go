@@ -0,0 +1,41 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+type DeleteScheduleUseCase struct {
+	databaseGateway     interfaces.IDatabaseGateway
+	findScheduleUseCase interfaces.IUseCase[dtos.ScheduleAccessDTO, dtos.Schedule]
+}
+
+func NewDeleteScheduleUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+	findScheduleUseCase interfaces.IUseCase[dtos.ScheduleAccessDTO, dtos.Schedule],
+) interfaces.IUseCase[dtos.ScheduleAccessDTO, any] {
+	return &DeleteScheduleUseCase{
+		databaseGateway:     databaseGateway,
+		findScheduleUseCase: findScheduleUseCase,
+	}
+}
+
+func (u *DeleteScheduleUseCase) Execute(ctx context.Context, data dtos.ScheduleAccessDTO) (any, error) {
+	if err := authorize(data.Scope, dtos.PERM_SCHEDULES_WRITE); err != nil {
+		return nil, err
+	}
+
+	if _, err := u.findScheduleUseCase.Execute(ctx, data); err != nil {
+		return nil, err
+	}
+
+	sql := "DELETE FROM schedules WHERE id = $1"
+	if err := u.databaseGateway.Exec(ctx, sql, data.ScheduleId); err != nil {
+		return nil, fmt.Errorf("unable to delete schedule: %v", err)
+	}
+
+	return nil, nil
+}
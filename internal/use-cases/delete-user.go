@@ -0,0 +1,65 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+type DeleteUserUseCase struct {
+	databaseGateway           interfaces.IDatabaseGateway
+	cacheGateway              interfaces.ICacheGateway
+	findUserUseCase           interfaces.IUseCase[string, dtos.User]
+	revokeUserSessionsUseCase interfaces.IUseCase[string, any]
+	accessTokenTTL            time.Duration
+}
+
+var (
+	ErrCannotDeleteSelf error = errs.BadRequest("you cannot delete your own account")
+)
+
+func NewDeleteUserUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+	cacheGateway interfaces.ICacheGateway,
+	findUserUseCase interfaces.IUseCase[string, dtos.User],
+	revokeUserSessionsUseCase interfaces.IUseCase[string, any],
+	accessTokenTTL time.Duration,
+) interfaces.IUseCase[dtos.DeleteUserDTO, any] {
+	return &DeleteUserUseCase{
+		databaseGateway:           databaseGateway,
+		cacheGateway:              cacheGateway,
+		findUserUseCase:           findUserUseCase,
+		revokeUserSessionsUseCase: revokeUserSessionsUseCase,
+		accessTokenTTL:            accessTokenTTL,
+	}
+}
+
+func (u *DeleteUserUseCase) Execute(ctx context.Context, data dtos.DeleteUserDTO) (any, error) {
+	if data.Id == data.ActorId {
+		return nil, ErrCannotDeleteSelf
+	}
+
+	if _, err := u.findUserUseCase.Execute(ctx, data.Id); err != nil {
+		return nil, err
+	}
+
+	// The tokens a deleted user still holds are rejected until they expire,
+	// the same way as a disabled user's.
+	if err := u.cacheGateway.Set(ctx, userDisabledKey(data.Id), "1", u.accessTokenTTL); err != nil {
+		return nil, fmt.Errorf("unable to revoke user tokens: %v", err)
+	}
+	if _, err := u.revokeUserSessionsUseCase.Execute(ctx, data.Id); err != nil {
+		return nil, err
+	}
+
+	sql := "DELETE FROM users WHERE id = $1"
+	if err := u.databaseGateway.Exec(ctx, sql, data.Id); err != nil {
+		return nil, fmt.Errorf("unable to delete user: %v", err)
+	}
+
+	return nil, nil
+}
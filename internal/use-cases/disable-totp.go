@@ -0,0 +1,53 @@
+package usecases
+
+import (
+	"context"
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+type DisableTotpUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+	cacheGateway    interfaces.ICacheGateway
+}
+
+func NewDisableTotpUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+	cacheGateway interfaces.ICacheGateway,
+) interfaces.IUseCase[dtos.TwoFactorCodeDTO, any] {
+	return &DisableTotpUseCase{
+		databaseGateway: databaseGateway,
+		cacheGateway:    cacheGateway,
+	}
+}
+
+// Execute removes the user's second factor. It needs a current code so a
+// hijacked session alone cannot downgrade the account, and is refused while
+// the policy requires two-factor authentication.
+func (u *DisableTotpUseCase) Execute(ctx context.Context, data dtos.TwoFactorCodeDTO) (any, error) {
+	policy, err := findAuthPolicy(ctx, u.databaseGateway)
+	if err != nil {
+		return nil, err
+	}
+	if policy.RequireTwoFactor {
+		return nil, ErrTwoFactorRequired
+	}
+
+	state, err := findTotpState(ctx, u.databaseGateway, data.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if !state.enabled {
+		return nil, ErrTotpNotEnrolled
+	}
+
+	valid, err := verifySecondFactor(ctx, u.databaseGateway, u.cacheGateway, data.UserId, state.secret, data.Code, true)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	return nil, clearTotp(ctx, u.databaseGateway, data.UserId)
+}
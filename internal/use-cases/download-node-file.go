@@ -0,0 +1,80 @@
+package usecases
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/JMCDynamics/maestro-server/internal/utils"
+)
+
+type DownloadNodeFileUseCase struct {
+	findNodeUseCase interfaces.IUseCase[string, dtos.Node]
+	client          *http.Client
+}
+
+func NewDownloadNodeFileUseCase(
+	findNodeUseCase interfaces.IUseCase[string, dtos.Node],
+) interfaces.IUseCase[dtos.DownloadFileDTO, dtos.FileStream] {
+	return &DownloadNodeFileUseCase{
+		findNodeUseCase: findNodeUseCase,
+		client:          utils.NewNodeAgentClient(0),
+	}
+}
+
+// Execute opens the file on the node agent and hands the open body back to the
+// caller, who is responsible for closing it. Range requests are forwarded so
+// interrupted downloads can be resumed, and the digest of the whole file is
+// looked up first so clients can verify what they assembled.
+func (u *DownloadNodeFileUseCase) Execute(ctx context.Context, data dtos.DownloadFileDTO) (dtos.FileStream, error) {
+	if err := authorize(data.Scope, dtos.PERM_FILES_READ); err != nil {
+		return dtos.FileStream{}, err
+	}
+
+	node, err := u.findNodeUseCase.Execute(ctx, data.NodeId)
+	if err != nil {
+		return dtos.FileStream{}, err
+	}
+
+	var checksum struct {
+		Sha256 string `json:"sha256"`
+	}
+	target := nodeAgentFileURL(node, "/files/checksum", url.Values{"path": {data.Path}})
+	if err := getNodeAgentJSON(ctx, u.client, target, &checksum); err != nil {
+		return dtos.FileStream{}, err
+	}
+
+	req, err := utils.NewNodeAgentRequest(ctx, http.MethodGet, nodeAgentFileURL(node, "/files", url.Values{"path": {data.Path}}), nil)
+	if err != nil {
+		return dtos.FileStream{}, err
+	}
+	if data.Range != "" {
+		req.Header.Set("Range", data.Range)
+	}
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return dtos.FileStream{}, ErrNodeAgentUnavailable
+	}
+
+	if err := checkNodeAgentResponse(resp); err != nil {
+		resp.Body.Close()
+		return dtos.FileStream{}, err
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	return dtos.FileStream{
+		Body:          resp.Body,
+		StatusCode:    resp.StatusCode,
+		ContentLength: resp.ContentLength,
+		ContentRange:  resp.Header.Get("Content-Range"),
+		ContentType:   contentType,
+		Sha256:        checksum.Sha256,
+	}, nil
+}
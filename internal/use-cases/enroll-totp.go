@@ -0,0 +1,37 @@
+package usecases
+
+import (
+	"context"
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+type EnrollTotpUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+	totpIssuer      string
+}
+
+func NewEnrollTotpUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+	totpIssuer string,
+) interfaces.IUseCase[string, dtos.TotpEnrollment] {
+	return &EnrollTotpUseCase{
+		databaseGateway: databaseGateway,
+		totpIssuer:      totpIssuer,
+	}
+}
+
+// Execute generates a secret for the user. It only takes effect once
+// confirmed with a code from the authenticator app.
+func (u *EnrollTotpUseCase) Execute(ctx context.Context, userId string) (dtos.TotpEnrollment, error) {
+	state, err := findTotpState(ctx, u.databaseGateway, userId)
+	if err != nil {
+		return dtos.TotpEnrollment{}, err
+	}
+
+	if state.enabled {
+		return dtos.TotpEnrollment{}, ErrTotpAlreadyEnabled
+	}
+
+	return startTotpEnrollment(ctx, u.databaseGateway, u.totpIssuer, userId, state.username)
+}
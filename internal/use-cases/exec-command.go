@@ -0,0 +1,239 @@
+package usecases
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/JMCDynamics/maestro-server/internal/services"
+	"github.com/JMCDynamics/maestro-server/internal/utils"
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	defaultExecTimeout  = time.Minute
+	maxPersistedOutput  = 1 << 20
+	execTruncatedNotice = "\n[output truncated]"
+)
+
+var (
+	ErrNodeAgentUnavailable error = errs.UpstreamUnavailable("unable to reach the node agent")
+)
+
+type ExecCommandUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+	findNodeUseCase interfaces.IUseCase[string, dtos.Node]
+	jobRegistry     *services.JobRegistryService
+	client          *http.Client
+}
+
+func NewExecCommandUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+	findNodeUseCase interfaces.IUseCase[string, dtos.Node],
+	jobRegistry *services.JobRegistryService,
+) interfaces.IUseCase[dtos.ExecDTO, dtos.Job] {
+	return &ExecCommandUseCase{
+		databaseGateway: databaseGateway,
+		findNodeUseCase: findNodeUseCase,
+		jobRegistry:     jobRegistry,
+		client:          utils.NewNodeAgentClient(0),
+	}
+}
+
+// Execute runs a command through the node agent's POST /exec endpoint, which
+// answers with a text/event-stream of dtos.JobOutput payloads terminated by an
+// "exit" event. The job is persisted before the call and updated once it ends.
+func (u *ExecCommandUseCase) Execute(ctx context.Context, data dtos.ExecDTO) (dtos.Job, error) {
+	if err := authorize(data.Scope, dtos.PERM_NODES_EXEC); err != nil {
+		return dtos.Job{}, err
+	}
+
+	node, err := u.findNodeUseCase.Execute(ctx, data.NodeId)
+	if err != nil {
+		return dtos.Job{}, err
+	}
+
+	job := dtos.Job{
+		Id:         ulid.Make().String(),
+		NodeId:     node.Id,
+		UserId:     data.UserId,
+		Command:    data.Command,
+		Args:       data.Args,
+		Env:        dtos.MaskEnv(data.Env),
+		WorkingDir: data.WorkingDir,
+		Status:     dtos.JOB_RUNNING,
+		CreatedAt:  time.Now(),
+	}
+	if job.Args == nil {
+		job.Args = []string{}
+	}
+
+	sql := "INSERT INTO jobs (id, node_id, user_id, command, args, env, working_dir, status, created_at) VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9)"
+	if err := u.databaseGateway.Exec(ctx, sql, job.Id, job.NodeId, job.UserId, job.Command, job.Args, job.Env, job.WorkingDir, job.Status, job.CreatedAt); err != nil {
+		return dtos.Job{}, fmt.Errorf("unable to create a job: %v", err)
+	}
+
+	if data.OnStart != nil {
+		data.OnStart(job)
+	}
+
+	timeout := defaultExecTimeout
+	if data.TimeoutMs > 0 {
+		timeout = time.Duration(data.TimeoutMs) * time.Millisecond
+	}
+
+	// the job outlives the request: only the job registry or its timeout
+	// cancel it, not the client going away
+	ctx = context.WithoutCancel(ctx)
+	cancelCtx, cancelJob := context.WithCancelCause(ctx)
+	defer cancelJob(nil)
+	runCtx, cancel := context.WithTimeout(cancelCtx, timeout)
+	defer cancel()
+
+	u.jobRegistry.Register(job.Id, cancelJob)
+	defer u.jobRegistry.Remove(job.Id)
+
+	var stdout, stderr strings.Builder
+	exitCode, runErr := u.run(runCtx, node, job, data.Env, func(output dtos.JobOutput) {
+		switch output.Stream {
+		case dtos.STDOUT:
+			appendOutput(&stdout, output.Data)
+		case dtos.STDERR:
+			appendOutput(&stderr, output.Data)
+		}
+
+		if data.OnOutput != nil {
+			data.OnOutput(output)
+		}
+	})
+
+	finishedAt := time.Now()
+	durationMs := finishedAt.Sub(job.CreatedAt).Milliseconds()
+
+	job.Stdout = stdout.String()
+	job.Stderr = stderr.String()
+	job.ExitCode = exitCode
+	job.DurationMs = &durationMs
+	job.FinishedAt = &finishedAt
+
+	switch {
+	case errors.Is(runCtx.Err(), context.DeadlineExceeded):
+		job.Status = dtos.JOB_TIMED_OUT
+	case errors.Is(runCtx.Err(), context.Canceled):
+		job.Status = dtos.JOB_CANCELLED
+		if cause := context.Cause(runCtx); !errors.Is(cause, context.Canceled) {
+			job.Stderr += cause.Error()
+		}
+	case runErr != nil:
+		job.Status = dtos.JOB_FAILED
+		job.Stderr += runErr.Error()
+	case exitCode != nil && *exitCode == 0:
+		job.Status = dtos.JOB_SUCCEEDED
+	default:
+		job.Status = dtos.JOB_FAILED
+	}
+
+	sql = "UPDATE jobs SET status = $1, exit_code = $2, stdout = $3, stderr = $4, duration_ms = $5, finished_at = $6 WHERE id = $7"
+	if err := u.databaseGateway.Exec(ctx, sql, job.Status, job.ExitCode, job.Stdout, job.Stderr, job.DurationMs, job.FinishedAt, job.Id); err != nil {
+		// still finish the job, or it would look RUNNING forever
+		utils.Logger(ctx).Error().Err(err).Str("job-id", job.Id).Msg("unable to store job output")
+
+		sql = "UPDATE jobs SET status = $1, exit_code = $2, duration_ms = $3, finished_at = $4 WHERE id = $5"
+		if err := u.databaseGateway.Exec(ctx, sql, job.Status, job.ExitCode, job.DurationMs, job.FinishedAt, job.Id); err != nil {
+			return job, fmt.Errorf("unable to update job: %v", err)
+		}
+	}
+
+	return job, nil
+}
+
+// run sends the job to the node agent with env, the unmasked environment the
+// job was requested with.
+func (u *ExecCommandUseCase) run(ctx context.Context, node dtos.Node, job dtos.Job, env map[string]string, onOutput func(dtos.JobOutput)) (*int, error) {
+	payload, err := json.Marshal(map[string]any{
+		"command":    job.Command,
+		"args":       job.Args,
+		"env":        env,
+		"workingDir": job.WorkingDir,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := utils.NewNodeAgentRequest(ctx, http.MethodPost, utils.NodeAgentURL(node.VpnAddress, "/exec"), bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, ErrNodeAgentUnavailable
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("node agent answered %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if err == io.EOF {
+				return nil, errors.New("node agent closed the stream before exit")
+			}
+			return nil, err
+		}
+
+		data, ok := strings.CutPrefix(strings.TrimRight(line, "\r\n"), "data:")
+		if !ok {
+			continue
+		}
+
+		var output dtos.JobOutput
+		if err := json.Unmarshal([]byte(strings.TrimSpace(data)), &output); err != nil {
+			continue
+		}
+
+		onOutput(output)
+
+		if output.Stream == dtos.EXIT {
+			return output.ExitCode, nil
+		}
+	}
+}
+
+func appendOutput(builder *strings.Builder, data string) {
+	if builder.Len() >= maxPersistedOutput {
+		return
+	}
+
+	if builder.Len()+len(data) > maxPersistedOutput {
+		// back off to the start of a character, Postgres refuses text that
+		// ends halfway through one
+		cut := maxPersistedOutput - builder.Len()
+		for cut > 0 && !utf8.RuneStart(data[cut]) {
+			cut--
+		}
+
+		builder.WriteString(data[:cut])
+		builder.WriteString(execTruncatedNotice)
+		return
+	}
+
+	builder.WriteString(data)
+}
@@ -0,0 +1,43 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+type FindApiKeysUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+}
+
+func NewFindApiKeysUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+) interfaces.IUseCase[string, []dtos.ApiKey] {
+	return &FindApiKeysUseCase{
+		databaseGateway: databaseGateway,
+	}
+}
+
+// Execute lists the user's keys. Only the prefix of each key is returned.
+func (u *FindApiKeysUseCase) Execute(ctx context.Context, userId string) ([]dtos.ApiKey, error) {
+	sql := "SELECT " + apiKeyColumns + " FROM api_keys WHERE user_id = $1 ORDER BY created_at"
+	resultSet, err := u.databaseGateway.Query(ctx, sql, userId)
+	if err != nil {
+		return []dtos.ApiKey{}, errors.New("unable to find api keys")
+	}
+	defer resultSet.Close()
+
+	apiKeys := []dtos.ApiKey{}
+	for resultSet.Next() {
+		apiKey, err := scanApiKey(resultSet)
+		if err != nil {
+			return nil, err
+		}
+
+		apiKeys = append(apiKeys, apiKey)
+	}
+
+	return apiKeys, resultSet.Err()
+}
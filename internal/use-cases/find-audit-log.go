@@ -0,0 +1,73 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+const defaultAuditLimit = 100
+
+type FindAuditLogUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+}
+
+func NewFindAuditLogUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+) interfaces.IUseCase[dtos.FindAuditDTO, dtos.AuditPage] {
+	return &FindAuditLogUseCase{
+		databaseGateway: databaseGateway,
+	}
+}
+
+// Execute returns one page of entries, newest first. Ids are ULIDs, so they
+// sort by creation time and double as the cursor.
+func (u *FindAuditLogUseCase) Execute(ctx context.Context, data dtos.FindAuditDTO) (dtos.AuditPage, error) {
+	limit := data.Limit
+	if limit <= 0 {
+		limit = defaultAuditLimit
+	}
+
+	sql := `SELECT id, actor_id, api_key_id, action, node_id, target, ip_address, request_id, outcome, status_code, created_at
+		FROM audit_log
+		WHERE ($1 = '' OR actor_id = $1)
+		AND ($2 = '' OR node_id = $2)
+		AND ($3 = '' OR action = $3)
+		AND ($4::timestamp IS NULL OR created_at >= $4)
+		AND ($5::timestamp IS NULL OR created_at < $5)
+		AND ($6 = '' OR id < $6)
+		ORDER BY id DESC
+		LIMIT $7`
+	// one extra row tells whether there is a next page
+	resultSet, err := u.databaseGateway.Query(ctx, sql,
+		data.ActorId, data.NodeId, data.Action, data.From, data.To, data.Cursor, limit+1,
+	)
+	if err != nil {
+		return dtos.AuditPage{}, errors.New("unable to find audit log")
+	}
+	defer resultSet.Close()
+
+	page := dtos.AuditPage{Entries: []dtos.AuditEntry{}}
+	for resultSet.Next() {
+		var entry dtos.AuditEntry
+		err := resultSet.Scan(
+			&entry.Id, &entry.ActorId, &entry.ApiKeyId, &entry.Action, &entry.NodeId, &entry.Target,
+			&entry.IpAddress, &entry.RequestId, &entry.Outcome, &entry.StatusCode, &entry.CreatedAt,
+		)
+		if err != nil {
+			return dtos.AuditPage{}, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+
+		page.Entries = append(page.Entries, entry)
+	}
+
+	if len(page.Entries) > limit {
+		page.Entries = page.Entries[:limit]
+		page.NextCursor = page.Entries[limit-1].Id
+	}
+
+	return page, nil
+}
@@ -0,0 +1,23 @@
+package usecases
+
+import (
+	"context"
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+type FindAuthPolicyUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+}
+
+func NewFindAuthPolicyUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+) interfaces.IUseCase[any, dtos.AuthPolicy] {
+	return &FindAuthPolicyUseCase{
+		databaseGateway: databaseGateway,
+	}
+}
+
+func (u *FindAuthPolicyUseCase) Execute(ctx context.Context, _ any) (dtos.AuthPolicy, error) {
+	return findAuthPolicy(ctx, u.databaseGateway)
+}
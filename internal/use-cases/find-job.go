@@ -0,0 +1,63 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+type FindJobUseCase struct {
+	databaseGateway      interfaces.IDatabaseGateway
+	canAccessNodeUseCase interfaces.IUseCase[dtos.NodeAccessDTO, bool]
+}
+
+var (
+	ErrJobNotFound error = errs.NotFound("job not found")
+)
+
+func NewFindJobUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+	canAccessNodeUseCase interfaces.IUseCase[dtos.NodeAccessDTO, bool],
+) interfaces.IUseCase[dtos.JobAccessDTO, dtos.Job] {
+	return &FindJobUseCase{
+		databaseGateway:      databaseGateway,
+		canAccessNodeUseCase: canAccessNodeUseCase,
+	}
+}
+
+// Execute answers ErrJobNotFound for jobs on nodes outside the scope, so that
+// their output cannot be read.
+func (u *FindJobUseCase) Execute(ctx context.Context, data dtos.JobAccessDTO) (dtos.Job, error) {
+	sql := `SELECT id, node_id, COALESCE(user_id, ''), command, args, env, working_dir, status, exit_code, stdout, stderr, duration_ms, created_at, finished_at
+		FROM jobs WHERE id = $1`
+	resultSet, err := u.databaseGateway.Query(ctx, sql, data.JobId)
+	if err != nil {
+		return dtos.Job{}, errors.New("unable to find job")
+	}
+	defer resultSet.Close()
+
+	if !resultSet.Next() {
+		return dtos.Job{}, ErrJobNotFound
+	}
+
+	var job dtos.Job
+	if err := resultSet.Scan(&job.Id, &job.NodeId, &job.UserId, &job.Command, &job.Args, &job.Env, &job.WorkingDir, &job.Status, &job.ExitCode, &job.Stdout, &job.Stderr, &job.DurationMs, &job.CreatedAt, &job.FinishedAt); err != nil {
+		return dtos.Job{}, fmt.Errorf("failed to scan job: %w", err)
+	}
+	// jobs stored before env values were masked still hold them
+	job.Env = dtos.MaskEnv(job.Env)
+
+	allowed, err := u.canAccessNodeUseCase.Execute(ctx, dtos.NodeAccessDTO{Scope: data.Scope, NodeId: job.NodeId})
+	if err != nil {
+		return dtos.Job{}, err
+	}
+	if !allowed {
+		return dtos.Job{}, ErrJobNotFound
+	}
+
+	return job, nil
+}
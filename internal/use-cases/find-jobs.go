@@ -0,0 +1,56 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+const defaultJobsLimit = 50
+
+type FindJobsUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+}
+
+func NewFindJobsUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+) interfaces.IUseCase[dtos.FindJobsDTO, []dtos.Job] {
+	return &FindJobsUseCase{
+		databaseGateway: databaseGateway,
+	}
+}
+
+func (u *FindJobsUseCase) Execute(ctx context.Context, data dtos.FindJobsDTO) ([]dtos.Job, error) {
+	limit := data.Limit
+	if limit <= 0 {
+		limit = defaultJobsLimit
+	}
+
+	sql := `SELECT id, node_id, COALESCE(user_id, ''), command, args, env, working_dir, status, exit_code, duration_ms, created_at, finished_at
+		FROM jobs
+		WHERE ` + scopedByNodeFilter("node_id") + ` AND ($3 = '' OR node_id = $3) AND ($4 = '' OR status::text = $4)
+		ORDER BY created_at DESC
+		LIMIT $5`
+	resultSet, err := u.databaseGateway.Query(ctx, sql, data.Scope.All, data.Scope.UserId, data.NodeId, data.Status, limit)
+	if err != nil {
+		return []dtos.Job{}, errors.New("unable to find jobs")
+	}
+	defer resultSet.Close()
+
+	jobs := []dtos.Job{}
+	for resultSet.Next() {
+		var job dtos.Job
+		if err := resultSet.Scan(&job.Id, &job.NodeId, &job.UserId, &job.Command, &job.Args, &job.Env, &job.WorkingDir, &job.Status, &job.ExitCode, &job.DurationMs, &job.CreatedAt, &job.FinishedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		// jobs stored before env values were masked still hold them
+		job.Env = dtos.MaskEnv(job.Env)
+
+		jobs = append(jobs, job)
+	}
+
+	return jobs, resultSet.Err()
+}
@@ -0,0 +1,63 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+const nodeGroupColumns = `g.id, g.name, g.description, g.created_at, g.updated_at,
+	COALESCE((SELECT array_agg(node_id ORDER BY node_id) FROM node_group_members WHERE group_id = g.id), '{}'),
+	COALESCE((SELECT array_agg(user_id ORDER BY user_id) FROM user_node_groups WHERE group_id = g.id), '{}')`
+
+type FindNodeGroupUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+}
+
+var (
+	ErrNodeGroupNotFound error = errs.NotFound("node group not found")
+)
+
+func NewFindNodeGroupUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+) interfaces.IUseCase[string, dtos.NodeGroup] {
+	return &FindNodeGroupUseCase{
+		databaseGateway: databaseGateway,
+	}
+}
+
+func (u *FindNodeGroupUseCase) Execute(ctx context.Context, id string) (dtos.NodeGroup, error) {
+	sql := "SELECT " + nodeGroupColumns + " FROM node_groups g WHERE g.id = $1"
+	resultSet, err := u.databaseGateway.Query(ctx, sql, id)
+	if err != nil {
+		return dtos.NodeGroup{}, errors.New("unable to find node group")
+	}
+	defer resultSet.Close()
+
+	if !resultSet.Next() {
+		return dtos.NodeGroup{}, ErrNodeGroupNotFound
+	}
+
+	return scanNodeGroup(resultSet)
+}
+
+func scanNodeGroup(resultSet interfaces.ResultSet) (dtos.NodeGroup, error) {
+	var group dtos.NodeGroup
+	if err := resultSet.Scan(
+		&group.Id,
+		&group.Name,
+		&group.Description,
+		&group.CreatedAt,
+		&group.UpdatedAt,
+		&group.NodeIds,
+		&group.UserIds,
+	); err != nil {
+		return dtos.NodeGroup{}, fmt.Errorf("failed to scan node group: %w", err)
+	}
+
+	return group, nil
+}
@@ -0,0 +1,42 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+type FindNodeGroupsUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+}
+
+func NewFindNodeGroupsUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+) interfaces.IUseCase[any, []dtos.NodeGroup] {
+	return &FindNodeGroupsUseCase{
+		databaseGateway: databaseGateway,
+	}
+}
+
+func (u *FindNodeGroupsUseCase) Execute(ctx context.Context, _ any) ([]dtos.NodeGroup, error) {
+	sql := "SELECT " + nodeGroupColumns + " FROM node_groups g ORDER BY g.name"
+	resultSet, err := u.databaseGateway.Query(ctx, sql)
+	if err != nil {
+		return []dtos.NodeGroup{}, errors.New("unable to find node groups")
+	}
+	defer resultSet.Close()
+
+	groups := []dtos.NodeGroup{}
+	for resultSet.Next() {
+		group, err := scanNodeGroup(resultSet)
+		if err != nil {
+			return nil, err
+		}
+
+		groups = append(groups, group)
+	}
+
+	return groups, resultSet.Err()
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
 	"github.com/JMCDynamics/maestro-server/internal/interfaces"
 )
 
@@ -18,7 +19,7 @@ type FindNodeUseCase struct {
 }
 
 var (
-	ErrNodeNotFound error = errors.New("failed to search for node")
+	ErrNodeNotFound error = errs.NotFound("node not found")
 )
 
 func NewFindNodeUseCase(
@@ -31,9 +32,9 @@ func NewFindNodeUseCase(
 	}
 }
 
-func (u *FindNodeUseCase) Execute(id string) (dtos.Node, error) {
-	sql := "SELECT id, name, operating_system, vpn_address FROM nodes WHERE id = $1"
-	resultSet, err := u.databaseGateway.Query(context.Background(), sql, id)
+func (u *FindNodeUseCase) Execute(ctx context.Context, id string) (dtos.Node, error) {
+	sql := "SELECT id, name, operating_system, vpn_address, labels FROM nodes WHERE id = $1"
+	resultSet, err := u.databaseGateway.Query(ctx, sql, id)
 	if err != nil {
 		return dtos.Node{}, errors.New("unable to find node")
 	}
@@ -44,11 +45,11 @@ func (u *FindNodeUseCase) Execute(id string) (dtos.Node, error) {
 	}
 
 	var node dtos.Node
-	if err := resultSet.Scan(&node.Id, &node.Name, &node.OperatingSystem, &node.VpnAddress); err != nil {
+	if err := resultSet.Scan(&node.Id, &node.Name, &node.OperatingSystem, &node.VpnAddress, &node.Labels); err != nil {
 		return dtos.Node{}, fmt.Errorf("failed to scan node: %w", err)
 	}
 
-	status, err := u.cacheGateway.Get(context.Background(), node.Id)
+	status, err := u.cacheGateway.Get(ctx, node.Id)
 	if err != nil {
 		status = dtos.DOWN
 	}
@@ -59,9 +60,6 @@ func (u *FindNodeUseCase) Execute(id string) (dtos.Node, error) {
 
 	contentString := string(contentBytes)
 	if contentString != "" {
-		fmt.Println(base64.StdEncoding.EncodeToString(
-			[]byte(strings.ReplaceAll(contentString, "\"", "")),
-		))
 		node.VpnConfig = base64.StdEncoding.EncodeToString(
 			[]byte(strings.ReplaceAll(contentString, "\"", "")),
 		)
@@ -17,16 +17,16 @@ type FindNodesUseCase struct {
 func NewFindNodesUseCase(
 	databaseGateway interfaces.IDatabaseGateway,
 	cacheGateway interfaces.ICacheGateway,
-) interfaces.IUseCase[any, []dtos.Node] {
+) interfaces.IUseCase[dtos.NodeScope, []dtos.Node] {
 	return &FindNodesUseCase{
 		databaseGateway: databaseGateway,
 		cacheGateway:    cacheGateway,
 	}
 }
 
-func (u *FindNodesUseCase) Execute(_ any) ([]dtos.Node, error) {
-	sql := "SELECT id, name, operating_system, vpn_address FROM nodes"
-	resultSet, err := u.databaseGateway.Query(context.Background(), sql)
+func (u *FindNodesUseCase) Execute(ctx context.Context, scope dtos.NodeScope) ([]dtos.Node, error) {
+	sql := "SELECT id, name, operating_system, vpn_address, labels FROM nodes WHERE " + scopedNodesFilter
+	resultSet, err := u.databaseGateway.Query(ctx, sql, scope.All, scope.UserId)
 	if err != nil {
 		return []dtos.Node{}, errors.New("unable to find nodes")
 	}
@@ -35,11 +35,11 @@ func (u *FindNodesUseCase) Execute(_ any) ([]dtos.Node, error) {
 	nodes := []dtos.Node{}
 	for resultSet.Next() {
 		var node dtos.Node
-		if err := resultSet.Scan(&node.Id, &node.Name, &node.OperatingSystem, &node.VpnAddress); err != nil {
+		if err := resultSet.Scan(&node.Id, &node.Name, &node.OperatingSystem, &node.VpnAddress, &node.Labels); err != nil {
 			return nil, fmt.Errorf("failed to scan node: %w", err)
 		}
 
-		status, err := u.cacheGateway.Get(context.Background(), node.Id)
+		status, err := u.cacheGateway.Get(ctx, node.Id)
 		if err != nil {
 			status = dtos.DOWN
 		}
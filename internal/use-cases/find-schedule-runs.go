@@ -0,0 +1,54 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+const defaultScheduleRunsLimit = 50
+
+type FindScheduleRunsUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+}
+
+func NewFindScheduleRunsUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+) interfaces.IUseCase[dtos.FindScheduleRunsDTO, []dtos.ScheduleRun] {
+	return &FindScheduleRunsUseCase{
+		databaseGateway: databaseGateway,
+	}
+}
+
+func (u *FindScheduleRunsUseCase) Execute(ctx context.Context, data dtos.FindScheduleRunsDTO) ([]dtos.ScheduleRun, error) {
+	limit := data.Limit
+	if limit <= 0 {
+		limit = defaultScheduleRunsLimit
+	}
+
+	sql := `SELECT id, schedule_id, status, attempts, results, COALESCE(error, ''), scheduled_for, started_at, finished_at
+		FROM schedule_runs
+		WHERE schedule_id = $3 AND schedule_id IN (SELECT id FROM schedules WHERE ` + scopedSchedulesFilter + `)
+		ORDER BY scheduled_for DESC
+		LIMIT $4`
+	resultSet, err := u.databaseGateway.Query(ctx, sql, data.Scope.All, data.Scope.UserId, data.ScheduleId, limit)
+	if err != nil {
+		return []dtos.ScheduleRun{}, errors.New("unable to find schedule runs")
+	}
+	defer resultSet.Close()
+
+	runs := []dtos.ScheduleRun{}
+	for resultSet.Next() {
+		var run dtos.ScheduleRun
+		if err := resultSet.Scan(&run.Id, &run.ScheduleId, &run.Status, &run.Attempts, &run.Results, &run.Error, &run.ScheduledFor, &run.StartedAt, &run.FinishedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan schedule run: %w", err)
+		}
+
+		runs = append(runs, run)
+	}
+
+	return runs, resultSet.Err()
+}
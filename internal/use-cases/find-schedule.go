@@ -0,0 +1,72 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+const scheduleColumns = "id, name, cron_expression, selector, action_type, action, max_retries, retry_delay_ms, enabled, COALESCE(created_by, ''), next_run_at, last_run_at, created_at"
+
+// scopedSchedulesFilter restricts a query on schedules to the caller's scope,
+// with the same parameters as scopedNodesFilter. Schedules run with their
+// creator's scope, so callers who do not see every node only see their own.
+const scopedSchedulesFilter = "($1 OR created_by = $2)"
+
+type FindScheduleUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+}
+
+var (
+	ErrScheduleNotFound error = errs.NotFound("schedule not found")
+)
+
+func NewFindScheduleUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+) interfaces.IUseCase[dtos.ScheduleAccessDTO, dtos.Schedule] {
+	return &FindScheduleUseCase{
+		databaseGateway: databaseGateway,
+	}
+}
+
+func (u *FindScheduleUseCase) Execute(ctx context.Context, data dtos.ScheduleAccessDTO) (dtos.Schedule, error) {
+	sql := "SELECT " + scheduleColumns + " FROM schedules WHERE " + scopedSchedulesFilter + " AND id = $3"
+	resultSet, err := u.databaseGateway.Query(ctx, sql, data.Scope.All, data.Scope.UserId, data.ScheduleId)
+	if err != nil {
+		return dtos.Schedule{}, errors.New("unable to find schedule")
+	}
+	defer resultSet.Close()
+
+	if !resultSet.Next() {
+		return dtos.Schedule{}, ErrScheduleNotFound
+	}
+
+	return scanSchedule(resultSet)
+}
+
+func scanSchedule(resultSet interfaces.ResultSet) (dtos.Schedule, error) {
+	var schedule dtos.Schedule
+	if err := resultSet.Scan(
+		&schedule.Id,
+		&schedule.Name,
+		&schedule.CronExpression,
+		&schedule.Selector,
+		&schedule.ActionType,
+		&schedule.Action,
+		&schedule.MaxRetries,
+		&schedule.RetryDelayMs,
+		&schedule.Enabled,
+		&schedule.CreatedBy,
+		&schedule.NextRunAt,
+		&schedule.LastRunAt,
+		&schedule.CreatedAt,
+	); err != nil {
+		return dtos.Schedule{}, fmt.Errorf("failed to scan schedule: %w", err)
+	}
+
+	return schedule, nil
+}
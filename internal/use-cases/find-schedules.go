@@ -0,0 +1,42 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+type FindSchedulesUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+}
+
+func NewFindSchedulesUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+) interfaces.IUseCase[dtos.NodeScope, []dtos.Schedule] {
+	return &FindSchedulesUseCase{
+		databaseGateway: databaseGateway,
+	}
+}
+
+func (u *FindSchedulesUseCase) Execute(ctx context.Context, scope dtos.NodeScope) ([]dtos.Schedule, error) {
+	sql := "SELECT " + scheduleColumns + " FROM schedules WHERE " + scopedSchedulesFilter + " ORDER BY created_at"
+	resultSet, err := u.databaseGateway.Query(ctx, sql, scope.All, scope.UserId)
+	if err != nil {
+		return []dtos.Schedule{}, errors.New("unable to find schedules")
+	}
+	defer resultSet.Close()
+
+	schedules := []dtos.Schedule{}
+	for resultSet.Next() {
+		schedule, err := scanSchedule(resultSet)
+		if err != nil {
+			return nil, err
+		}
+
+		schedules = append(schedules, schedule)
+	}
+
+	return schedules, resultSet.Err()
+}
@@ -0,0 +1,55 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+type FindSessionsUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+}
+
+func NewFindSessionsUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+) interfaces.IUseCase[string, []dtos.Session] {
+	return &FindSessionsUseCase{
+		databaseGateway: databaseGateway,
+	}
+}
+
+// Execute lists the user's sessions that can still be refreshed.
+func (u *FindSessionsUseCase) Execute(ctx context.Context, userId string) ([]dtos.Session, error) {
+	sql := `SELECT id, user_id, user_agent, ip_address, created_at, last_used_at, expires_at
+		FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > now()
+		ORDER BY last_used_at DESC`
+	resultSet, err := u.databaseGateway.Query(ctx, sql, userId)
+	if err != nil {
+		return []dtos.Session{}, errors.New("unable to find sessions")
+	}
+	defer resultSet.Close()
+
+	sessions := []dtos.Session{}
+	for resultSet.Next() {
+		var session dtos.Session
+		if err := resultSet.Scan(
+			&session.Id,
+			&session.UserId,
+			&session.UserAgent,
+			&session.IpAddress,
+			&session.CreatedAt,
+			&session.LastUsedAt,
+			&session.ExpiresAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	return sessions, resultSet.Err()
+}
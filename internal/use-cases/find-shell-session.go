@@ -0,0 +1,48 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+type FindShellSessionUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+}
+
+var (
+	ErrShellSessionNotFound error = errs.NotFound("shell session not found")
+)
+
+func NewFindShellSessionUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+) interfaces.IUseCase[string, dtos.ShellSession] {
+	return &FindShellSessionUseCase{
+		databaseGateway: databaseGateway,
+	}
+}
+
+func (u *FindShellSessionUseCase) Execute(ctx context.Context, id string) (dtos.ShellSession, error) {
+	sql := `SELECT id, node_id, COALESCE(user_id, ''), shell, recording_path, COALESCE(close_reason, ''), started_at, ended_at
+		FROM shell_sessions WHERE id = $1`
+	resultSet, err := u.databaseGateway.Query(ctx, sql, id)
+	if err != nil {
+		return dtos.ShellSession{}, errors.New("unable to find shell session")
+	}
+	defer resultSet.Close()
+
+	if !resultSet.Next() {
+		return dtos.ShellSession{}, ErrShellSessionNotFound
+	}
+
+	var session dtos.ShellSession
+	if err := resultSet.Scan(&session.Id, &session.NodeId, &session.UserId, &session.Shell, &session.RecordingPath, &session.CloseReason, &session.StartedAt, &session.EndedAt); err != nil {
+		return dtos.ShellSession{}, fmt.Errorf("failed to scan shell session: %w", err)
+	}
+
+	return session, nil
+}
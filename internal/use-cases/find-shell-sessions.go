@@ -0,0 +1,54 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+const defaultShellSessionsLimit = 50
+
+type FindShellSessionsUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+}
+
+func NewFindShellSessionsUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+) interfaces.IUseCase[dtos.FindShellSessionsDTO, []dtos.ShellSession] {
+	return &FindShellSessionsUseCase{
+		databaseGateway: databaseGateway,
+	}
+}
+
+func (u *FindShellSessionsUseCase) Execute(ctx context.Context, data dtos.FindShellSessionsDTO) ([]dtos.ShellSession, error) {
+	limit := data.Limit
+	if limit <= 0 {
+		limit = defaultShellSessionsLimit
+	}
+
+	sql := `SELECT id, node_id, COALESCE(user_id, ''), shell, recording_path, COALESCE(close_reason, ''), started_at, ended_at
+		FROM shell_sessions
+		WHERE ($1 = '' OR node_id = $1) AND (NOT $2 OR ended_at IS NULL)
+		ORDER BY started_at DESC
+		LIMIT $3`
+	resultSet, err := u.databaseGateway.Query(ctx, sql, data.NodeId, data.Active, limit)
+	if err != nil {
+		return []dtos.ShellSession{}, errors.New("unable to find shell sessions")
+	}
+	defer resultSet.Close()
+
+	sessions := []dtos.ShellSession{}
+	for resultSet.Next() {
+		var session dtos.ShellSession
+		if err := resultSet.Scan(&session.Id, &session.NodeId, &session.UserId, &session.Shell, &session.RecordingPath, &session.CloseReason, &session.StartedAt, &session.EndedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan shell session: %w", err)
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	return sessions, resultSet.Err()
+}
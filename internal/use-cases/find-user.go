@@ -0,0 +1,53 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+const userColumns = "id, username, role, disabled, totp_enabled, created_at, updated_at, last_login_at"
+
+type FindUserUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+}
+
+var (
+	ErrUserNotFound error = errs.NotFound("user not found")
+)
+
+func NewFindUserUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+) interfaces.IUseCase[string, dtos.User] {
+	return &FindUserUseCase{
+		databaseGateway: databaseGateway,
+	}
+}
+
+func (u *FindUserUseCase) Execute(ctx context.Context, id string) (dtos.User, error) {
+	sql := "SELECT " + userColumns + " FROM users WHERE id = $1"
+	resultSet, err := u.databaseGateway.Query(ctx, sql, id)
+	if err != nil {
+		return dtos.User{}, errors.New("unable to find user")
+	}
+	defer resultSet.Close()
+
+	if !resultSet.Next() {
+		return dtos.User{}, ErrUserNotFound
+	}
+
+	return scanUser(resultSet)
+}
+
+func scanUser(resultSet interfaces.ResultSet) (dtos.User, error) {
+	var user dtos.User
+	if err := resultSet.Scan(&user.Id, &user.Username, &user.Role, &user.Disabled, &user.TwoFactorEnabled, &user.CreatedAt, &user.UpdatedAt, &user.LastLoginAt); err != nil {
+		return dtos.User{}, fmt.Errorf("failed to scan user: %w", err)
+	}
+
+	return user, nil
+}
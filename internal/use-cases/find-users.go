@@ -0,0 +1,42 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+type FindUsersUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+}
+
+func NewFindUsersUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+) interfaces.IUseCase[any, []dtos.User] {
+	return &FindUsersUseCase{
+		databaseGateway: databaseGateway,
+	}
+}
+
+func (u *FindUsersUseCase) Execute(ctx context.Context, _ any) ([]dtos.User, error) {
+	sql := "SELECT " + userColumns + " FROM users ORDER BY username"
+	resultSet, err := u.databaseGateway.Query(ctx, sql)
+	if err != nil {
+		return []dtos.User{}, errors.New("unable to find users")
+	}
+	defer resultSet.Close()
+
+	users := []dtos.User{}
+	for resultSet.Next() {
+		user, err := scanUser(resultSet)
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, user)
+	}
+
+	return users, resultSet.Err()
+}
@@ -0,0 +1,52 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+type IsTokenRevokedUseCase struct {
+	cacheGateway interfaces.ICacheGateway
+}
+
+func NewIsTokenRevokedUseCase(
+	cacheGateway interfaces.ICacheGateway,
+) interfaces.IUseCase[dtos.TokenClaims, bool] {
+	return &IsTokenRevokedUseCase{
+		cacheGateway: cacheGateway,
+	}
+}
+
+// Execute reports whether the token was logged out, belongs to a revoked
+// session or a disabled user, or was issued before its user's sessions were
+// revoked.
+func (u *IsTokenRevokedUseCase) Execute(ctx context.Context, claims dtos.TokenClaims) (bool, error) {
+	keys := []string{revokedTokenKey(claims.Jti), revokedSessionKey(claims.SessionId), userDisabledKey(claims.UserId)}
+	for _, key := range keys {
+		_, err := u.cacheGateway.Get(ctx, key)
+		if err == nil {
+			return true, nil
+		}
+		if err != interfaces.ErrKeyNotFound {
+			return false, fmt.Errorf("unable to check token denylist: %v", err)
+		}
+	}
+
+	value, err := u.cacheGateway.Get(ctx, revokedBeforeKey(claims.UserId))
+	if err == interfaces.ErrKeyNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("unable to check session revocation: %v", err)
+	}
+
+	revokedBefore, err := parseRevokedBefore(value)
+	if err != nil {
+		return false, err
+	}
+
+	return claims.IssuedAt.Before(revokedBefore), nil
+}
@@ -0,0 +1,45 @@
+package usecases
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/JMCDynamics/maestro-server/internal/utils"
+)
+
+type ListNodeFilesUseCase struct {
+	findNodeUseCase interfaces.IUseCase[string, dtos.Node]
+	client          *http.Client
+}
+
+func NewListNodeFilesUseCase(
+	findNodeUseCase interfaces.IUseCase[string, dtos.Node],
+) interfaces.IUseCase[dtos.NodeFileDTO, []dtos.FileEntry] {
+	return &ListNodeFilesUseCase{
+		findNodeUseCase: findNodeUseCase,
+		client:          utils.NewNodeAgentClient(30 * time.Second),
+	}
+}
+
+func (u *ListNodeFilesUseCase) Execute(ctx context.Context, data dtos.NodeFileDTO) ([]dtos.FileEntry, error) {
+	if err := authorize(data.Scope, dtos.PERM_FILES_READ); err != nil {
+		return nil, err
+	}
+
+	node, err := u.findNodeUseCase.Execute(ctx, data.NodeId)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []dtos.FileEntry{}
+	target := nodeAgentFileURL(node, "/files/list", url.Values{"path": {data.Path}})
+	if err := getNodeAgentJSON(ctx, u.client, target, &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -1,10 +1,11 @@
 package usecases
 
 import (
+	"context"
 	"time"
 
 	"github.com/JMCDynamics/maestro-server/internal/interfaces"
-	"github.com/rs/zerolog/log"
+	"github.com/JMCDynamics/maestro-server/internal/utils"
 )
 
 type LoggerUseCase[T any, R any] struct {
@@ -15,22 +16,24 @@ func NewLoggerUseCase[T any, R any](actor interfaces.IUseCase[T, R]) *LoggerUseC
 	return &LoggerUseCase[T, R]{actor: actor}
 }
 
-func (u *LoggerUseCase[T, R]) Execute(props T) (R, error) {
+func (u *LoggerUseCase[T, R]) Execute(ctx context.Context, props T) (R, error) {
 	start := time.Now()
 
-	log.Debug().
+	logger := utils.Logger(ctx)
+
+	logger.Debug().
 		Str("event", "use_case_execution").
 		Str("use_case", "LoggerUseCase").
 		Interface("input", props).
 		Time("timestamp", start).
 		Msg("executing use case")
 
-	result, err := u.actor.Execute(props)
+	result, err := u.actor.Execute(ctx, props)
 
 	duration := time.Since(start)
 
 	if err != nil {
-		log.Error().
+		logger.Error().
 			Str("event", "use_case_failed").
 			Str("use_case", "LoggerUseCase").
 			Err(err).
@@ -40,7 +43,7 @@ func (u *LoggerUseCase[T, R]) Execute(props T) (R, error) {
 		return result, err
 	}
 
-	log.Debug().
+	logger.Debug().
 		Str("event", "use_case_success").
 		Str("use_case", "LoggerUseCase").
 		Interface("output", result).
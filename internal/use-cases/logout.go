@@ -0,0 +1,40 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+type LogoutUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+	cacheGateway    interfaces.ICacheGateway
+	accessTokenTTL  time.Duration
+}
+
+func NewLogoutUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+	cacheGateway interfaces.ICacheGateway,
+	accessTokenTTL time.Duration,
+) interfaces.IUseCase[dtos.TokenClaims, any] {
+	return &LogoutUseCase{
+		databaseGateway: databaseGateway,
+		cacheGateway:    cacheGateway,
+		accessTokenTTL:  accessTokenTTL,
+	}
+}
+
+// Execute denylists the token until it would have expired anyway and ends
+// its session so the refresh token cannot mint new ones.
+func (u *LogoutUseCase) Execute(ctx context.Context, claims dtos.TokenClaims) (any, error) {
+	if ttl := time.Until(claims.ExpiresAt); ttl > 0 {
+		if err := u.cacheGateway.Set(ctx, revokedTokenKey(claims.Jti), claims.UserId, ttl); err != nil {
+			return nil, fmt.Errorf("unable to revoke token: %v", err)
+		}
+	}
+
+	return nil, revokeSession(ctx, u.databaseGateway, u.cacheGateway, u.accessTokenTTL, claims.SessionId)
+}
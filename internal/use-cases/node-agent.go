@@ -0,0 +1,57 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/utils"
+)
+
+var (
+	ErrFileNotFound error = errs.NotFound("file not found on node")
+)
+
+func nodeAgentFileURL(node dtos.Node, path string, query url.Values) string {
+	return utils.NodeAgentURL(node.VpnAddress, path+"?"+query.Encode())
+}
+
+// checkNodeAgentResponse turns a non-2xx answer from the node agent into an
+// error, keeping the agent's message so callers can surface it.
+func checkNodeAgentResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrFileNotFound
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return fmt.Errorf("node agent answered %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+func getNodeAgentJSON(ctx context.Context, client *http.Client, target string, dest any) error {
+	req, err := utils.NewNodeAgentRequest(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return ErrNodeAgentUnavailable
+	}
+	defer resp.Body.Close()
+
+	if err := checkNodeAgentResponse(resp); err != nil {
+		return err
+	}
+
+	return json.NewDecoder(resp.Body).Decode(dest)
+}
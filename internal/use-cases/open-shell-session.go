@@ -0,0 +1,245 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/JMCDynamics/maestro-server/internal/services"
+	"github.com/JMCDynamics/maestro-server/internal/utils"
+	"github.com/gorilla/websocket"
+	"github.com/oklog/ulid/v2"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultShellCols = 80
+	defaultShellRows = 24
+)
+
+var (
+	ErrUnsupportedShell error = errs.BadRequest("shell not supported on this operating system")
+)
+
+var shellsByOperatingSystem = map[dtos.OperatingSystem][]string{
+	dtos.LINUX:   {"bash", "sh"},
+	dtos.WINDOWS: {"powershell", "cmd"},
+}
+
+type OpenShellSessionUseCase struct {
+	databaseGateway     interfaces.IDatabaseGateway
+	shellSessionService *services.ShellSessionService
+	idleTimeout         time.Duration
+	recordingsPath      string
+	dialer              *websocket.Dialer
+}
+
+func NewOpenShellSessionUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+	shellSessionService *services.ShellSessionService,
+	idleTimeout time.Duration,
+	recordingsPath string,
+) interfaces.IUseCase[dtos.OpenShellDTO, dtos.ShellSession] {
+	return &OpenShellSessionUseCase{
+		databaseGateway:     databaseGateway,
+		shellSessionService: shellSessionService,
+		idleTimeout:         idleTimeout,
+		recordingsPath:      recordingsPath,
+		dialer:              websocket.DefaultDialer,
+	}
+}
+
+// Execute bridges an already upgraded browser connection to a PTY on the node
+// agent's /shell WebSocket and blocks until either side ends the session.
+func (u *OpenShellSessionUseCase) Execute(ctx context.Context, data dtos.OpenShellDTO) (dtos.ShellSession, error) {
+	if err := authorize(data.Scope, dtos.PERM_NODES_SHELL); err != nil {
+		return dtos.ShellSession{}, err
+	}
+
+	shell, err := shellFor(data.Node.OperatingSystem, data.Shell)
+	if err != nil {
+		return dtos.ShellSession{}, err
+	}
+
+	cols, rows := data.Cols, data.Rows
+	if cols == 0 {
+		cols = defaultShellCols
+	}
+	if rows == 0 {
+		rows = defaultShellRows
+	}
+
+	query := url.Values{}
+	query.Set("shell", shell)
+	query.Set("cols", strconv.Itoa(cols))
+	query.Set("rows", strconv.Itoa(rows))
+
+	dialCtx, span := utils.Tracer().Start(ctx, "node-agent GET /shell", trace.WithSpanKind(trace.SpanKindClient))
+	agentConn, _, err := u.dialer.DialContext(dialCtx, utils.NodeAgentWebSocketURL(data.Node.VpnAddress, "/shell?"+query.Encode()), utils.NodeAgentHeader(dialCtx))
+	utils.EndSpan(span, err)
+	if err != nil {
+		return dtos.ShellSession{}, ErrNodeAgentUnavailable
+	}
+	defer agentConn.Close()
+
+	id := ulid.Make().String()
+	session := dtos.ShellSession{
+		Id:            id,
+		NodeId:        data.Node.Id,
+		UserId:        data.UserId,
+		Shell:         shell,
+		RecordingPath: filepath.Join(u.recordingsPath, id+".cast"),
+		StartedAt:     time.Now(),
+	}
+
+	// the recording comes first, so a failure here leaves no session row
+	// without an end behind
+	recorder, err := services.NewShellRecorder(session.RecordingPath, cols, rows)
+	if err != nil {
+		return dtos.ShellSession{}, err
+	}
+	defer recorder.Close()
+
+	sql := "INSERT INTO shell_sessions (id, node_id, user_id, shell, recording_path, started_at) VALUES($1,$2,$3,$4,$5,$6)"
+	if err := u.databaseGateway.Exec(ctx, sql, session.Id, session.NodeId, session.UserId, session.Shell, session.RecordingPath, session.StartedAt); err != nil {
+		recorder.Close()
+		os.Remove(session.RecordingPath)
+		return dtos.ShellSession{}, fmt.Errorf("unable to create a shell session: %v", err)
+	}
+
+	var (
+		once   sync.Once
+		reason string
+		done   = make(chan struct{})
+	)
+	closeSession := func(r string) {
+		once.Do(func() {
+			reason = r
+			close(done)
+		})
+	}
+
+	u.shellSessionService.Register(session, closeSession)
+	defer u.shellSessionService.Remove(session.Id)
+
+	var writeMu sync.Mutex
+	writeClient := func(message dtos.ShellMessage) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+
+		return data.Conn.WriteJSON(message)
+	}
+
+	go func() {
+		for {
+			var message dtos.ShellMessage
+			if err := agentConn.ReadJSON(&message); err != nil {
+				closeSession("node agent disconnected")
+				return
+			}
+
+			if message.Type == dtos.SHELL_OUTPUT {
+				recorder.Output(message.Data)
+			}
+
+			if err := writeClient(message); err != nil {
+				closeSession("client disconnected")
+				return
+			}
+
+			if message.Type == dtos.SHELL_EXIT {
+				closeSession("shell exited")
+				return
+			}
+		}
+	}()
+
+	activity := make(chan struct{}, 1)
+	go func() {
+		for {
+			var message dtos.ShellMessage
+			if err := data.Conn.ReadJSON(&message); err != nil {
+				closeSession("client disconnected")
+				return
+			}
+
+			switch message.Type {
+			case dtos.SHELL_INPUT:
+			case dtos.SHELL_RESIZE:
+				recorder.Resize(message.Cols, message.Rows)
+			default:
+				continue
+			}
+
+			select {
+			case activity <- struct{}{}:
+			default:
+			}
+
+			if err := agentConn.WriteJSON(message); err != nil {
+				closeSession("node agent disconnected")
+				return
+			}
+		}
+	}()
+
+	idle := time.NewTimer(u.idleTimeout)
+	defer idle.Stop()
+
+loop:
+	for {
+		select {
+		case <-done:
+			break loop
+		case <-activity:
+			idle.Reset(u.idleTimeout)
+		case <-idle.C:
+			closeSession("idle timeout")
+		}
+	}
+
+	writeClient(dtos.ShellMessage{Type: dtos.SHELL_CLOSED, Reason: reason})
+	data.Conn.WriteControl(
+		websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, reason),
+		time.Now().Add(time.Second),
+	)
+
+	endedAt := time.Now()
+	session.EndedAt = &endedAt
+	session.CloseReason = reason
+
+	sql = "UPDATE shell_sessions SET ended_at = $1, close_reason = $2 WHERE id = $3"
+	if err := u.databaseGateway.Exec(context.WithoutCancel(ctx), sql, session.EndedAt, session.CloseReason, session.Id); err != nil {
+		return session, fmt.Errorf("unable to update shell session: %v", err)
+	}
+
+	return session, nil
+}
+
+func shellFor(operatingSystem dtos.OperatingSystem, requested string) (string, error) {
+	shells, ok := shellsByOperatingSystem[operatingSystem]
+	if !ok {
+		return "", ErrUnsupportedShell
+	}
+
+	if requested == "" {
+		return shells[0], nil
+	}
+
+	for _, shell := range shells {
+		if shell == requested {
+			return shell, nil
+		}
+	}
+
+	return "", ErrUnsupportedShell
+}
@@ -0,0 +1,40 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/oklog/ulid/v2"
+)
+
+type RecordAuditEventUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+}
+
+func NewRecordAuditEventUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+) interfaces.IUseCase[dtos.AuditEntry, any] {
+	return &RecordAuditEventUseCase{
+		databaseGateway: databaseGateway,
+	}
+}
+
+func (u *RecordAuditEventUseCase) Execute(ctx context.Context, entry dtos.AuditEntry) (any, error) {
+	entry.Id = ulid.Make().String()
+	entry.CreatedAt = time.Now()
+
+	sql := `INSERT INTO audit_log (id, actor_id, api_key_id, action, node_id, target, ip_address, request_id, outcome, status_code, created_at)
+		VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)`
+	err := u.databaseGateway.Exec(ctx, sql,
+		entry.Id, entry.ActorId, entry.ApiKeyId, entry.Action, entry.NodeId, entry.Target,
+		entry.IpAddress, entry.RequestId, entry.Outcome, entry.StatusCode, entry.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to record audit event: %v", err)
+	}
+
+	return nil, nil
+}
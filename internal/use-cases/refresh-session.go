@@ -0,0 +1,124 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/JMCDynamics/maestro-server/internal/utils"
+)
+
+var (
+	ErrInvalidRefreshToken error = errs.Unauthorized("invalid or expired refresh token")
+	ErrRefreshTokenReused  error = errs.Unauthorized("refresh token was already used; the session has been revoked")
+)
+
+type RefreshSessionUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+	cacheGateway    interfaces.ICacheGateway
+	jwtKeySet       *utils.JWTKeySet
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+}
+
+func NewRefreshSessionUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+	cacheGateway interfaces.ICacheGateway,
+	jwtKeySet *utils.JWTKeySet,
+	accessTokenTTL time.Duration,
+	refreshTokenTTL time.Duration,
+) interfaces.IUseCase[dtos.RefreshSessionDTO, dtos.TokenPair] {
+	return &RefreshSessionUseCase{
+		databaseGateway: databaseGateway,
+		cacheGateway:    cacheGateway,
+		jwtKeySet:       jwtKeySet,
+		accessTokenTTL:  accessTokenTTL,
+		refreshTokenTTL: refreshTokenTTL,
+	}
+}
+
+// Execute rotates the refresh token: the presented one is spent and a new pair
+// is returned. Presenting a spent token means it leaked, so the whole session
+// is revoked.
+func (u *RefreshSessionUseCase) Execute(ctx context.Context, data dtos.RefreshSessionDTO) (dtos.TokenPair, error) {
+	tokenHash := hashToken(data.RefreshToken)
+
+	sql := `SELECT rt.session_id, rt.used_at, rt.expires_at, s.revoked_at, s.user_id, u.role, u.disabled
+		FROM refresh_tokens rt
+		JOIN sessions s ON s.id = rt.session_id
+		JOIN users u ON u.id = s.user_id
+		WHERE rt.token_hash = $1`
+	resultSet, err := u.databaseGateway.Query(ctx, sql, tokenHash)
+	if err != nil {
+		return dtos.TokenPair{}, errors.New("unable to find refresh token")
+	}
+
+	if !resultSet.Next() {
+		resultSet.Close()
+		return dtos.TokenPair{}, ErrInvalidRefreshToken
+	}
+
+	var (
+		sessionId string
+		usedAt    *time.Time
+		expiresAt time.Time
+		revokedAt *time.Time
+		userId    string
+		role      dtos.Role
+		disabled  bool
+	)
+	err = resultSet.Scan(&sessionId, &usedAt, &expiresAt, &revokedAt, &userId, &role, &disabled)
+	resultSet.Close()
+	if err != nil {
+		return dtos.TokenPair{}, fmt.Errorf("failed to scan refresh token: %w", err)
+	}
+
+	if revokedAt != nil || time.Now().After(expiresAt) {
+		return dtos.TokenPair{}, ErrInvalidRefreshToken
+	}
+
+	if usedAt != nil {
+		return dtos.TokenPair{}, u.reused(ctx, sessionId, userId)
+	}
+
+	if disabled {
+		return dtos.TokenPair{}, ErrUserDisabled
+	}
+
+	// Spend the token atomically so two concurrent refreshes cannot both win.
+	sql = "UPDATE refresh_tokens SET used_at = now() WHERE token_hash = $1 AND used_at IS NULL RETURNING session_id"
+	resultSet, err = u.databaseGateway.Query(ctx, sql, tokenHash)
+	if err != nil {
+		return dtos.TokenPair{}, fmt.Errorf("unable to rotate refresh token: %v", err)
+	}
+	spent := resultSet.Next()
+	resultSet.Close()
+	if !spent {
+		return dtos.TokenPair{}, u.reused(ctx, sessionId, userId)
+	}
+
+	newExpiresAt := time.Now().Add(u.refreshTokenTTL)
+	sql = "UPDATE sessions SET last_used_at = now(), expires_at = $1, user_agent = $2, ip_address = $3 WHERE id = $4"
+	if err := u.databaseGateway.Exec(ctx, sql, newExpiresAt, data.UserAgent, data.IpAddress, sessionId); err != nil {
+		return dtos.TokenPair{}, fmt.Errorf("unable to update session: %v", err)
+	}
+
+	return issueTokenPair(ctx, u.databaseGateway, u.jwtKeySet, u.accessTokenTTL, newExpiresAt, sessionId, userId, role)
+}
+
+func (u *RefreshSessionUseCase) reused(ctx context.Context, sessionId, userId string) error {
+	utils.Logger(ctx).Warn().
+		Str("session-id", sessionId).
+		Str("user-id", userId).
+		Msg("refresh token reuse detected, revoking session")
+
+	if err := revokeSession(ctx, u.databaseGateway, u.cacheGateway, u.accessTokenTTL, sessionId); err != nil {
+		return err
+	}
+
+	return ErrRefreshTokenReused
+}
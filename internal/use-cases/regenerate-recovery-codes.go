@@ -0,0 +1,42 @@
+package usecases
+
+import (
+	"context"
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+type RegenerateRecoveryCodesUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+	cacheGateway    interfaces.ICacheGateway
+}
+
+func NewRegenerateRecoveryCodesUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+	cacheGateway interfaces.ICacheGateway,
+) interfaces.IUseCase[dtos.TwoFactorCodeDTO, []string] {
+	return &RegenerateRecoveryCodesUseCase{
+		databaseGateway: databaseGateway,
+		cacheGateway:    cacheGateway,
+	}
+}
+
+func (u *RegenerateRecoveryCodesUseCase) Execute(ctx context.Context, data dtos.TwoFactorCodeDTO) ([]string, error) {
+	state, err := findTotpState(ctx, u.databaseGateway, data.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if !state.enabled {
+		return nil, ErrTotpNotEnrolled
+	}
+
+	valid, err := verifySecondFactor(ctx, u.databaseGateway, u.cacheGateway, data.UserId, state.secret, data.Code, false)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	return replaceRecoveryCodes(ctx, u.databaseGateway, data.UserId)
+}
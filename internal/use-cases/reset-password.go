@@ -0,0 +1,38 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+type ResetPasswordUseCase struct {
+	databaseGateway           interfaces.IDatabaseGateway
+	findUserUseCase           interfaces.IUseCase[string, dtos.User]
+	revokeUserSessionsUseCase interfaces.IUseCase[string, any]
+}
+
+func NewResetPasswordUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+	findUserUseCase interfaces.IUseCase[string, dtos.User],
+	revokeUserSessionsUseCase interfaces.IUseCase[string, any],
+) interfaces.IUseCase[dtos.ResetPasswordDTO, any] {
+	return &ResetPasswordUseCase{
+		databaseGateway:           databaseGateway,
+		findUserUseCase:           findUserUseCase,
+		revokeUserSessionsUseCase: revokeUserSessionsUseCase,
+	}
+}
+
+func (u *ResetPasswordUseCase) Execute(ctx context.Context, data dtos.ResetPasswordDTO) (any, error) {
+	if _, err := u.findUserUseCase.Execute(ctx, data.UserId); err != nil {
+		return nil, err
+	}
+
+	if err := setPassword(ctx, u.databaseGateway, data.UserId, data.NewPassword); err != nil {
+		return nil, err
+	}
+
+	return u.revokeUserSessionsUseCase.Execute(ctx, data.UserId)
+}
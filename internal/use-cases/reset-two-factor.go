@@ -0,0 +1,33 @@
+package usecases
+
+import (
+	"context"
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+type ResetTwoFactorUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+	findUserUseCase interfaces.IUseCase[string, dtos.User]
+}
+
+func NewResetTwoFactorUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+	findUserUseCase interfaces.IUseCase[string, dtos.User],
+) interfaces.IUseCase[string, any] {
+	return &ResetTwoFactorUseCase{
+		databaseGateway: databaseGateway,
+		findUserUseCase: findUserUseCase,
+	}
+}
+
+// Execute lets an admin recover a user who lost both their authenticator and
+// recovery codes. The user enrols again on their next login if the policy
+// requires it.
+func (u *ResetTwoFactorUseCase) Execute(ctx context.Context, userId string) (any, error) {
+	if _, err := u.findUserUseCase.Execute(ctx, userId); err != nil {
+		return nil, err
+	}
+
+	return nil, clearTotp(ctx, u.databaseGateway, userId)
+}
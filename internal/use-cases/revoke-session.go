@@ -0,0 +1,51 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+var (
+	ErrSessionNotFound error = errs.NotFound("session not found")
+)
+
+type RevokeSessionUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+	cacheGateway    interfaces.ICacheGateway
+	accessTokenTTL  time.Duration
+}
+
+func NewRevokeSessionUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+	cacheGateway interfaces.ICacheGateway,
+	accessTokenTTL time.Duration,
+) interfaces.IUseCase[dtos.RevokeSessionDTO, any] {
+	return &RevokeSessionUseCase{
+		databaseGateway: databaseGateway,
+		cacheGateway:    cacheGateway,
+		accessTokenTTL:  accessTokenTTL,
+	}
+}
+
+// Execute revokes one of the user's own active sessions. Sessions of other
+// users are reported as not found.
+func (u *RevokeSessionUseCase) Execute(ctx context.Context, data dtos.RevokeSessionDTO) (any, error) {
+	sql := "SELECT id FROM sessions WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL"
+	resultSet, err := u.databaseGateway.Query(ctx, sql, data.SessionId, data.UserId)
+	if err != nil {
+		return nil, errors.New("unable to find session")
+	}
+	found := resultSet.Next()
+	resultSet.Close()
+
+	if !found {
+		return nil, ErrSessionNotFound
+	}
+
+	return nil, revokeSession(ctx, u.databaseGateway, u.cacheGateway, u.accessTokenTTL, data.SessionId)
+}
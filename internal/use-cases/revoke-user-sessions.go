@@ -0,0 +1,41 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+type RevokeUserSessionsUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+	cacheGateway    interfaces.ICacheGateway
+	findUserUseCase interfaces.IUseCase[string, dtos.User]
+	accessTokenTTL  time.Duration
+}
+
+func NewRevokeUserSessionsUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+	cacheGateway interfaces.ICacheGateway,
+	findUserUseCase interfaces.IUseCase[string, dtos.User],
+	accessTokenTTL time.Duration,
+) interfaces.IUseCase[string, any] {
+	return &RevokeUserSessionsUseCase{
+		databaseGateway: databaseGateway,
+		cacheGateway:    cacheGateway,
+		findUserUseCase: findUserUseCase,
+		accessTokenTTL:  accessTokenTTL,
+	}
+}
+
+// Execute ends every session of the user and rejects every access token
+// issued to them up to now. The marker only needs to outlive the
+// longest-lived access token.
+func (u *RevokeUserSessionsUseCase) Execute(ctx context.Context, userId string) (any, error) {
+	if _, err := u.findUserUseCase.Execute(ctx, userId); err != nil {
+		return nil, err
+	}
+
+	return nil, revokeUserSessions(ctx, u.databaseGateway, u.cacheGateway, u.accessTokenTTL, userId)
+}
@@ -0,0 +1,317 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/JMCDynamics/maestro-server/internal/services"
+	"github.com/JMCDynamics/maestro-server/internal/utils"
+	"github.com/oklog/ulid/v2"
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	// the fire lock only has to outlive the tick that moves next_run_at
+	// past the planned time; a shorter one lets a failed tick retry sooner
+	scheduleFireLockTTL     = 5 * time.Minute
+	scheduleRunningLockTTL  = time.Hour
+	scheduleExecConcurrency = 10
+)
+
+type RunDueSchedulesUseCase struct {
+	databaseGateway    interfaces.IDatabaseGateway
+	cacheGateway       interfaces.ICacheGateway
+	findUserUseCase    interfaces.IUseCase[string, dtos.User]
+	findNodesUseCase   interfaces.IUseCase[dtos.NodeScope, []dtos.Node]
+	broadcastUseCase   interfaces.IUseCase[dtos.BroadcastDTO, map[string]dtos.BroadcastResult]
+	execCommandUseCase interfaces.IUseCase[dtos.ExecDTO, dtos.Job]
+	jobRegistry        *services.JobRegistryService
+}
+
+func NewRunDueSchedulesUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+	cacheGateway interfaces.ICacheGateway,
+	findUserUseCase interfaces.IUseCase[string, dtos.User],
+	findNodesUseCase interfaces.IUseCase[dtos.NodeScope, []dtos.Node],
+	broadcastUseCase interfaces.IUseCase[dtos.BroadcastDTO, map[string]dtos.BroadcastResult],
+	execCommandUseCase interfaces.IUseCase[dtos.ExecDTO, dtos.Job],
+	jobRegistry *services.JobRegistryService,
+) interfaces.IUseCase[time.Time, int] {
+	return &RunDueSchedulesUseCase{
+		databaseGateway:    databaseGateway,
+		cacheGateway:       cacheGateway,
+		findUserUseCase:    findUserUseCase,
+		findNodesUseCase:   findNodesUseCase,
+		broadcastUseCase:   broadcastUseCase,
+		execCommandUseCase: execCommandUseCase,
+		jobRegistry:        jobRegistry,
+	}
+}
+
+// Execute fires every enabled schedule whose next run is due at now and
+// returns how many runs this instance started. Each run is claimed through a
+// Redis lock keyed by schedule and planned time, so when several servers tick
+// at once only one of them fires it. Runs execute in the background.
+func (u *RunDueSchedulesUseCase) Execute(ctx context.Context, now time.Time) (int, error) {
+	sql := "SELECT " + scheduleColumns + " FROM schedules WHERE enabled AND next_run_at <= $1"
+	resultSet, err := u.databaseGateway.Query(ctx, sql, now)
+	if err != nil {
+		return 0, errors.New("unable to find due schedules")
+	}
+
+	schedules := []dtos.Schedule{}
+	for resultSet.Next() {
+		schedule, err := scanSchedule(resultSet)
+		if err != nil {
+			resultSet.Close()
+			return 0, err
+		}
+
+		schedules = append(schedules, schedule)
+	}
+	resultSet.Close()
+
+	fired := 0
+	for _, schedule := range schedules {
+		cronSchedule, err := cron.ParseStandard(schedule.CronExpression)
+		if err != nil {
+			utils.Logger(ctx).Error().Err(err).Str("schedule-id", schedule.Id).Msg("invalid cron expression")
+			continue
+		}
+
+		scheduledFor := *schedule.NextRunAt
+		lockKey := fmt.Sprintf("scheduler:fire:%s:%d", schedule.Id, scheduledFor.Unix())
+		acquired, err := u.cacheGateway.SetIfNotExists(ctx, lockKey, "1", scheduleFireLockTTL)
+		if err != nil || !acquired {
+			continue
+		}
+
+		sql := "UPDATE schedules SET next_run_at = $1, last_run_at = $2 WHERE id = $3"
+		if err := u.databaseGateway.Exec(ctx, sql, cronSchedule.Next(now), now, schedule.Id); err != nil {
+			return fired, fmt.Errorf("unable to update schedule: %v", err)
+		}
+
+		fired++
+		go u.run(context.WithoutCancel(ctx), schedule, scheduledFor)
+	}
+
+	return fired, nil
+}
+
+func (u *RunDueSchedulesUseCase) run(ctx context.Context, schedule dtos.Schedule, scheduledFor time.Time) {
+	run := dtos.ScheduleRun{
+		Id:           ulid.Make().String(),
+		ScheduleId:   schedule.Id,
+		Status:       dtos.RUN_RUNNING,
+		Results:      map[string]dtos.ScheduleRunResult{},
+		ScheduledFor: scheduledFor,
+		StartedAt:    time.Now(),
+	}
+
+	sql := "INSERT INTO schedule_runs (id, schedule_id, status, scheduled_for, started_at) VALUES($1,$2,$3,$4,$5)"
+	if err := u.databaseGateway.Exec(ctx, sql, run.Id, run.ScheduleId, run.Status, run.ScheduledFor, run.StartedAt); err != nil {
+		utils.Logger(ctx).Error().Err(err).Str("schedule-id", schedule.Id).Msg("unable to create schedule run")
+		return
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	u.jobRegistry.Register(run.Id, cancel)
+	defer u.jobRegistry.Remove(run.Id)
+
+	runningKey := "scheduler:running:" + schedule.Id
+	acquired, err := u.cacheGateway.SetIfNotExists(ctx, runningKey, run.Id, scheduleRunningLockTTL)
+	switch {
+	case err != nil:
+		run.Status = dtos.RUN_FAILED
+		run.Error = "unable to acquire overlap lock"
+	case !acquired:
+		run.Status = dtos.RUN_SKIPPED
+		run.Error = "previous run is still in progress"
+	default:
+		u.execute(ctx, schedule, &run)
+
+		// the lock may have expired during a long run and been taken by a
+		// newer one, which must keep it
+		if _, err := u.cacheGateway.DeleteIfEquals(context.WithoutCancel(ctx), runningKey, run.Id); err != nil {
+			utils.Logger(ctx).Error().Err(err).Str("schedule-run-id", run.Id).Msg("unable to release overlap lock")
+		}
+	}
+
+	if cause := context.Cause(ctx); cause != nil {
+		run.Status = dtos.RUN_FAILED
+		run.Error = cause.Error()
+	}
+
+	// record the run even when it was interrupted
+	ctx = context.WithoutCancel(ctx)
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+
+	sql = "UPDATE schedule_runs SET status = $1, attempts = $2, results = $3, error = $4, finished_at = $5 WHERE id = $6"
+	if err := u.databaseGateway.Exec(ctx, sql, run.Status, run.Attempts, run.Results, run.Error, run.FinishedAt, run.Id); err != nil {
+		utils.Logger(ctx).Error().Err(err).Str("schedule-run-id", run.Id).Msg("unable to update schedule run")
+	}
+}
+
+// execute runs the action on every selected node the schedule's owner may
+// see, retrying only the nodes that failed until they succeed or the schedule
+// runs out of retries.
+func (u *RunDueSchedulesUseCase) execute(ctx context.Context, schedule dtos.Schedule, run *dtos.ScheduleRun) {
+	owner, err := u.findUserUseCase.Execute(ctx, schedule.CreatedBy)
+	if err != nil || owner.Disabled {
+		run.Status = dtos.RUN_FAILED
+		run.Error = "schedule owner no longer exists or is disabled"
+		return
+	}
+	scope := dtos.NewNodeScope(owner.Id, owner.Role, nil)
+
+	nodes, err := u.findNodesUseCase.Execute(ctx, scope)
+	if err != nil {
+		run.Status = dtos.RUN_FAILED
+		run.Error = err.Error()
+		return
+	}
+
+	pending := []string{}
+	for _, node := range nodes {
+		if schedule.Selector.Matches(node) {
+			pending = append(pending, node.Id)
+		}
+	}
+
+	for attempt := 1; attempt <= schedule.MaxRetries+1 && len(pending) > 0; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-time.After(time.Duration(schedule.RetryDelayMs) * time.Millisecond):
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		run.Attempts = attempt
+
+		var results map[string]dtos.ScheduleRunResult
+		switch schedule.ActionType {
+		case dtos.SCHEDULE_PROXY:
+			results = u.proxy(ctx, schedule, scope, pending)
+		case dtos.SCHEDULE_EXEC:
+			results = u.exec(ctx, schedule, scope, pending)
+		}
+
+		failed := []string{}
+		for _, nodeId := range pending {
+			result, ok := results[nodeId]
+			if !ok {
+				result = dtos.ScheduleRunResult{NodeId: nodeId, Error: "node no longer exists"}
+			}
+			result.Attempts = attempt
+			run.Results[nodeId] = result
+
+			if result.Error != "" {
+				failed = append(failed, nodeId)
+			}
+		}
+		pending = failed
+	}
+
+	if len(pending) > 0 {
+		run.Status = dtos.RUN_FAILED
+		run.Error = fmt.Sprintf("%d of %d nodes failed", len(pending), len(run.Results))
+		return
+	}
+
+	run.Status = dtos.RUN_SUCCEEDED
+}
+
+func (u *RunDueSchedulesUseCase) proxy(ctx context.Context, schedule dtos.Schedule, scope dtos.NodeScope, nodeIds []string) map[string]dtos.ScheduleRunResult {
+	results := map[string]dtos.ScheduleRunResult{}
+
+	responses, err := u.broadcastUseCase.Execute(ctx, dtos.BroadcastDTO{
+		Method:   schedule.Action.Method,
+		Path:     schedule.Action.Path,
+		Headers:  schedule.Action.Headers,
+		Body:     schedule.Action.Body,
+		Selector: dtos.NodeSelector{Ids: nodeIds},
+		Scope:    scope,
+	})
+	if err != nil {
+		for _, nodeId := range nodeIds {
+			results[nodeId] = dtos.ScheduleRunResult{NodeId: nodeId, Error: err.Error()}
+		}
+		return results
+	}
+
+	for nodeId, response := range responses {
+		result := dtos.ScheduleRunResult{
+			NodeId:     nodeId,
+			StatusCode: response.StatusCode,
+			Error:      response.Error,
+		}
+		if result.Error == "" && response.StatusCode >= 400 {
+			result.Error = fmt.Sprintf("node answered %d", response.StatusCode)
+		}
+
+		results[nodeId] = result
+	}
+
+	return results
+}
+
+func (u *RunDueSchedulesUseCase) exec(ctx context.Context, schedule dtos.Schedule, scope dtos.NodeScope, nodeIds []string) map[string]dtos.ScheduleRunResult {
+	var (
+		m       sync.Mutex
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, scheduleExecConcurrency)
+		results = map[string]dtos.ScheduleRunResult{}
+	)
+
+	for _, nodeId := range nodeIds {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(nodeId string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			job, err := u.execCommandUseCase.Execute(ctx, dtos.ExecDTO{
+				NodeId:     nodeId,
+				UserId:     schedule.CreatedBy,
+				Scope:      scope,
+				Command:    schedule.Action.Command,
+				Args:       schedule.Action.Args,
+				Env:        schedule.Action.Env,
+				WorkingDir: schedule.Action.WorkingDir,
+				TimeoutMs:  schedule.Action.TimeoutMs,
+			})
+
+			result := dtos.ScheduleRunResult{
+				NodeId:   nodeId,
+				JobId:    job.Id,
+				ExitCode: job.ExitCode,
+			}
+			switch {
+			case err != nil:
+				result.Error = err.Error()
+			case job.Status != dtos.JOB_SUCCEEDED:
+				result.Error = "job " + job.Status
+			}
+
+			m.Lock()
+			defer m.Unlock()
+
+			results[nodeId] = result
+		}(nodeId)
+	}
+
+	wg.Wait()
+
+	return results
+}
@@ -20,8 +20,8 @@ func NewSetNodeUpUseCase(
 	}
 }
 
-func (u *SetNodeUpUseCase) Execute(id string) (any, error) {
-	if err := u.cacheGateway.Set(context.Background(), id, dtos.UP, 5*time.Second); err != nil {
+func (u *SetNodeUpUseCase) Execute(ctx context.Context, id string) (any, error) {
+	if err := u.cacheGateway.Set(ctx, id, dtos.UP, 5*time.Second); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,66 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+// oidcLoginTTL bounds how long a user may take at the identity provider.
+const oidcLoginTTL = 10 * time.Minute
+
+func oidcStateKey(state string) string {
+	return "oidc:state:" + state
+}
+
+type StartOidcLoginUseCase struct {
+	cacheGateway            interfaces.ICacheGateway
+	identityProviderGateway interfaces.IIdentityProviderGateway
+}
+
+func NewStartOidcLoginUseCase(
+	cacheGateway interfaces.ICacheGateway,
+	identityProviderGateway interfaces.IIdentityProviderGateway,
+) interfaces.IUseCase[any, dtos.OidcLogin] {
+	return &StartOidcLoginUseCase{
+		cacheGateway:            cacheGateway,
+		identityProviderGateway: identityProviderGateway,
+	}
+}
+
+// Execute creates a single-use login state holding the nonce and PKCE
+// verifier, and returns it with the provider URL to redirect the browser to.
+func (u *StartOidcLoginUseCase) Execute(ctx context.Context, _ any) (dtos.OidcLogin, error) {
+	state, err := randomToken()
+	if err != nil {
+		return dtos.OidcLogin{}, fmt.Errorf("unable to generate login state: %v", err)
+	}
+
+	loginState := dtos.OidcLoginState{}
+	if loginState.Nonce, err = randomToken(); err != nil {
+		return dtos.OidcLogin{}, fmt.Errorf("unable to generate nonce: %v", err)
+	}
+	if loginState.CodeVerifier, err = randomToken(); err != nil {
+		return dtos.OidcLogin{}, fmt.Errorf("unable to generate code verifier: %v", err)
+	}
+
+	value, err := json.Marshal(loginState)
+	if err != nil {
+		return dtos.OidcLogin{}, err
+	}
+
+	if err := u.cacheGateway.Set(ctx, oidcStateKey(state), string(value), oidcLoginTTL); err != nil {
+		return dtos.OidcLogin{}, fmt.Errorf("unable to store login state: %v", err)
+	}
+
+	authURL, err := u.identityProviderGateway.AuthCodeURL(ctx, state, loginState.Nonce, loginState.CodeVerifier)
+	if err != nil {
+		return dtos.OidcLogin{}, err
+	}
+
+	return dtos.OidcLogin{State: state, AuthURL: authURL}, nil
+}
@@ -0,0 +1,45 @@
+package usecases
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/JMCDynamics/maestro-server/internal/utils"
+)
+
+type StatNodeFileUseCase struct {
+	findNodeUseCase interfaces.IUseCase[string, dtos.Node]
+	client          *http.Client
+}
+
+func NewStatNodeFileUseCase(
+	findNodeUseCase interfaces.IUseCase[string, dtos.Node],
+) interfaces.IUseCase[dtos.NodeFileDTO, dtos.FileEntry] {
+	return &StatNodeFileUseCase{
+		findNodeUseCase: findNodeUseCase,
+		client:          utils.NewNodeAgentClient(30 * time.Second),
+	}
+}
+
+func (u *StatNodeFileUseCase) Execute(ctx context.Context, data dtos.NodeFileDTO) (dtos.FileEntry, error) {
+	if err := authorize(data.Scope, dtos.PERM_FILES_READ); err != nil {
+		return dtos.FileEntry{}, err
+	}
+
+	node, err := u.findNodeUseCase.Execute(ctx, data.NodeId)
+	if err != nil {
+		return dtos.FileEntry{}, err
+	}
+
+	var entry dtos.FileEntry
+	target := nodeAgentFileURL(node, "/files/stat", url.Values{"path": {data.Path}})
+	if err := getNodeAgentJSON(ctx, u.client, target, &entry); err != nil {
+		return dtos.FileEntry{}, err
+	}
+
+	return entry, nil
+}
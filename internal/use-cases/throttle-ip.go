@@ -0,0 +1,80 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/JMCDynamics/maestro-server/internal/utils"
+)
+
+// ThrottleIpUseCase extends the per-IP lockout of POST /auth to another
+// credential check. Failures share the IP's counter with failed passwords, so
+// a locked out address is refused on every authentication route.
+type ThrottleIpUseCase[T any, R any] struct {
+	cacheGateway interfaces.ICacheGateway
+	actor        interfaces.IUseCase[T, R]
+	policy       dtos.LoginThrottlePolicy
+	ipAddress    func(T) string
+	failures     []error
+}
+
+// NewThrottleIpUseCase counts the errors in failures, matched with errors.Is,
+// against the IP address ipAddress returns for the input.
+func NewThrottleIpUseCase[T any, R any](
+	cacheGateway interfaces.ICacheGateway,
+	actor interfaces.IUseCase[T, R],
+	policy dtos.LoginThrottlePolicy,
+	ipAddress func(T) string,
+	failures ...error,
+) *ThrottleIpUseCase[T, R] {
+	return &ThrottleIpUseCase[T, R]{
+		cacheGateway: cacheGateway,
+		actor:        actor,
+		policy:       policy,
+		ipAddress:    ipAddress,
+		failures:     failures,
+	}
+}
+
+func (u *ThrottleIpUseCase[T, R]) Execute(ctx context.Context, props T) (R, error) {
+	var result R
+
+	// the attempt is counted before the actor runs, so requests sent in
+	// parallel cannot all get past the limit
+	throttle := loginThrottle{subject: "ip", value: u.ipAddress(props), maxAttempts: u.policy.MaxAttemptsPerIp}
+	reservation, err := reserveAttempt(ctx, u.cacheGateway, u.policy, throttle)
+	if err != nil {
+		return result, err
+	}
+
+	result, err = u.actor.Execute(ctx, props)
+
+	// a client hanging up must not keep its attempt off the record
+	recordCtx := context.WithoutCancel(ctx)
+	switch {
+	case err == nil || !u.failed(err):
+		releaseAttempts(recordCtx, u.cacheGateway, reservation)
+	case reservation.lockout > 0:
+		utils.Logger(recordCtx).Warn().
+			Str("event", "auth_lockout").
+			Str("throttle", throttle.subject).
+			Str("ip-address", throttle.value).
+			Int64("attempts", reservation.attempts).
+			Dur("lockout", reservation.lockout).
+			Msg("authentication locked out after repeated failures")
+	}
+
+	return result, err
+}
+
+func (u *ThrottleIpUseCase[T, R]) failed(err error) bool {
+	for _, failure := range u.failures {
+		if errors.Is(err, failure) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,233 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/JMCDynamics/maestro-server/internal/utils"
+)
+
+var (
+	ErrTooManyLoginAttempts error = errs.TooManyRequests("too many failed login attempts, try again later")
+)
+
+// LoginThrottledError is returned while a username or IP address is locked
+// out. It matches ErrTooManyLoginAttempts with errors.Is.
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return ErrTooManyLoginAttempts.Error()
+}
+
+func (e *LoginThrottledError) Unwrap() error {
+	return ErrTooManyLoginAttempts
+}
+
+type ThrottleLoginUseCase struct {
+	cacheGateway            interfaces.ICacheGateway
+	authenticateUserUseCase interfaces.IUseCase[dtos.AuthUserDTO, dtos.AuthResult]
+	recordAuditEventUseCase interfaces.IUseCase[dtos.AuditEntry, any]
+	policy                  dtos.LoginThrottlePolicy
+}
+
+// NewThrottleLoginUseCase wraps authenticateUserUseCase with per-username and
+// per-IP failure counters kept in Redis.
+func NewThrottleLoginUseCase(
+	cacheGateway interfaces.ICacheGateway,
+	authenticateUserUseCase interfaces.IUseCase[dtos.AuthUserDTO, dtos.AuthResult],
+	recordAuditEventUseCase interfaces.IUseCase[dtos.AuditEntry, any],
+	policy dtos.LoginThrottlePolicy,
+) interfaces.IUseCase[dtos.AuthUserDTO, dtos.AuthResult] {
+	return &ThrottleLoginUseCase{
+		cacheGateway:            cacheGateway,
+		authenticateUserUseCase: authenticateUserUseCase,
+		recordAuditEventUseCase: recordAuditEventUseCase,
+		policy:                  policy,
+	}
+}
+
+type loginThrottle struct {
+	subject     string
+	value       string
+	maxAttempts int
+}
+
+func (t loginThrottle) attemptsKey() string {
+	return "auth:attempts:" + t.subject + ":" + t.value
+}
+
+func (t loginThrottle) lockoutKey() string {
+	return "auth:lockout:" + t.subject + ":" + t.value
+}
+
+// lockout is how long to lock out after attempts failures: LockoutBase at the
+// limit, doubling with every further failure up to LockoutMax.
+func (t loginThrottle) lockout(policy dtos.LoginThrottlePolicy, attempts int64) time.Duration {
+	lockout := policy.LockoutBase
+	for i := int64(t.maxAttempts); i < attempts && lockout < policy.LockoutMax; i++ {
+		lockout *= 2
+	}
+
+	return min(lockout, policy.LockoutMax)
+}
+
+func (u *ThrottleLoginUseCase) Execute(ctx context.Context, data dtos.AuthUserDTO) (dtos.AuthResult, error) {
+	throttles := []loginThrottle{
+		{subject: "user", value: data.Username, maxAttempts: u.policy.MaxAttemptsPerUser},
+		{subject: "ip", value: data.IpAddress, maxAttempts: u.policy.MaxAttemptsPerIp},
+	}
+
+	// Attempts are counted before the password is checked, so guesses sent
+	// in parallel cannot all get past the limit, and locked out callers are
+	// refused before a lockout can be used to probe whether a guess was right.
+	reservations := make([]attemptReservation, 0, len(throttles))
+	for _, throttle := range throttles {
+		reservation, err := reserveAttempt(ctx, u.cacheGateway, u.policy, throttle)
+		if err != nil {
+			releaseAttempts(context.WithoutCancel(ctx), u.cacheGateway, reservations...)
+			return dtos.AuthResult{}, err
+		}
+		reservations = append(reservations, reservation)
+	}
+
+	result, err := u.authenticateUserUseCase.Execute(ctx, data)
+
+	// a client hanging up must not keep its attempt off the record
+	ctx = context.WithoutCancel(ctx)
+	switch err {
+	case nil:
+		clearAttempts(ctx, u.cacheGateway, throttles[0])
+		releaseAttempts(ctx, u.cacheGateway, reservations[1:]...)
+	case ErrInvalidCredentials:
+		for _, reservation := range reservations {
+			if reservation.lockout > 0 {
+				u.recordLockout(ctx, reservation, data)
+			}
+		}
+	default:
+		releaseAttempts(ctx, u.cacheGateway, reservations...)
+	}
+
+	return result, err
+}
+
+func (u *ThrottleLoginUseCase) recordLockout(ctx context.Context, reservation attemptReservation, data dtos.AuthUserDTO) {
+	throttle := reservation.throttle
+
+	utils.Logger(ctx).Warn().
+		Str("event", "auth_lockout").
+		Str("throttle", throttle.subject).
+		Str("username", data.Username).
+		Str("ip-address", data.IpAddress).
+		Int64("attempts", reservation.attempts).
+		Dur("lockout", reservation.lockout).
+		Msg("login locked out after repeated failures")
+
+	_, err := u.recordAuditEventUseCase.Execute(ctx, dtos.AuditEntry{
+		Action:    "auth.lockout",
+		Target:    throttle.subject + ":" + throttle.value,
+		IpAddress: data.IpAddress,
+		RequestId: utils.RequestId(ctx),
+		Outcome:   dtos.AUDIT_DENIED,
+	})
+	if err != nil {
+		utils.Logger(ctx).Error().Err(err).Str("action", "auth.lockout").Msg("unable to record audit event")
+	}
+}
+
+// attemptReservation is an attempt counted against a throttle before the
+// credential is checked. lockout is set when this attempt took the lockout.
+type attemptReservation struct {
+	throttle loginThrottle
+	window   time.Duration
+	attempts int64
+	lockout  time.Duration
+}
+
+// reserveAttempt counts an attempt against throttle and refuses it while the
+// throttle is locked out. The attempt that reaches the limit takes the
+// lockout itself, with SetIfNotExists, so that only one caller gets it and
+// the ones racing it are refused.
+func reserveAttempt(ctx context.Context, cacheGateway interfaces.ICacheGateway, policy dtos.LoginThrottlePolicy, throttle loginThrottle) (attemptReservation, error) {
+	reservation := attemptReservation{throttle: throttle, window: policy.Window}
+	if throttle.maxAttempts <= 0 {
+		return reservation, nil
+	}
+
+	retryAfter, err := lockoutRemaining(ctx, cacheGateway, throttle)
+	if err != nil {
+		return reservation, err
+	}
+	if retryAfter > 0 {
+		return reservation, &LoginThrottledError{RetryAfter: retryAfter}
+	}
+
+	reservation.attempts, err = cacheGateway.Increment(ctx, throttle.attemptsKey(), policy.Window)
+	if err != nil {
+		return reservation, fmt.Errorf("unable to count attempts: %v", err)
+	}
+	if reservation.attempts < int64(throttle.maxAttempts) {
+		return reservation, nil
+	}
+
+	lockout := throttle.lockout(policy, reservation.attempts)
+	acquired, err := cacheGateway.SetIfNotExists(ctx, throttle.lockoutKey(), "1", lockout)
+	if err != nil {
+		return reservation, fmt.Errorf("unable to lock out: %v", err)
+	}
+	if !acquired {
+		return reservation, &LoginThrottledError{RetryAfter: lockout}
+	}
+	reservation.lockout = lockout
+
+	return reservation, nil
+}
+
+// releaseAttempts gives back attempts that turned out not to be failures,
+// along with any lockout they took.
+func releaseAttempts(ctx context.Context, cacheGateway interfaces.ICacheGateway, reservations ...attemptReservation) {
+	for _, reservation := range reservations {
+		throttle := reservation.throttle
+		if throttle.maxAttempts <= 0 {
+			continue
+		}
+
+		if _, err := cacheGateway.Decrement(ctx, throttle.attemptsKey(), reservation.window); err != nil {
+			utils.Logger(ctx).Error().Err(err).Str("throttle", throttle.subject).Msg("unable to release attempt")
+		}
+		if reservation.lockout > 0 {
+			cacheGateway.Delete(ctx, throttle.lockoutKey())
+		}
+	}
+}
+
+// clearAttempts forgets the failures counted against throttle once the
+// credential it guards has been presented correctly.
+func clearAttempts(ctx context.Context, cacheGateway interfaces.ICacheGateway, throttle loginThrottle) {
+	cacheGateway.Delete(ctx, throttle.attemptsKey())
+	cacheGateway.Delete(ctx, throttle.lockoutKey())
+}
+
+// lockoutRemaining returns the longest lockout left on throttles, or zero
+// when none of them is locked out.
+func lockoutRemaining(ctx context.Context, cacheGateway interfaces.ICacheGateway, throttles ...loginThrottle) (time.Duration, error) {
+	var remaining time.Duration
+	for _, throttle := range throttles {
+		ttl, err := cacheGateway.TTL(ctx, throttle.lockoutKey())
+		if err == interfaces.ErrKeyNotFound {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		remaining = max(remaining, ttl)
+	}
+
+	return remaining, nil
+}
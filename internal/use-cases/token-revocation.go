@@ -0,0 +1,108 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+// Revocation keys live in Redis only for as long as the tokens they reject
+// could still be valid. They all contain ":" so the node heartbeat listener
+// ignores their expiry.
+func revokedTokenKey(jti string) string {
+	return "auth:revoked:" + jti
+}
+
+func revokedSessionKey(sessionId string) string {
+	return "auth:session-revoked:" + sessionId
+}
+
+// userDisabledKey marks a disabled user. No token can be issued to them
+// while it is set, so it only has to outlive the tokens issued before.
+func userDisabledKey(userId string) string {
+	return "auth:user-disabled:" + userId
+}
+
+func revokedBeforeKey(userId string) string {
+	return "auth:revoked-before:" + userId
+}
+
+// revokedBeforeValue rounds up to the next second because iat has second
+// precision; a token issued in the same second as the revocation is rejected.
+func revokedBeforeValue(now time.Time) string {
+	return strconv.FormatInt(now.Unix()+1, 10)
+}
+
+func parseRevokedBefore(value string) (time.Time, error) {
+	unix, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid revocation timestamp: %v", err)
+	}
+
+	return time.Unix(unix, 0), nil
+}
+
+// revokeSession ends a session so its refresh token stops working, and marks
+// it in Redis so access tokens already issued for it are rejected until they
+// expire.
+func revokeSession(
+	ctx context.Context,
+	databaseGateway interfaces.IDatabaseGateway,
+	cacheGateway interfaces.ICacheGateway,
+	accessTokenTTL time.Duration,
+	sessionId string,
+) error {
+	sql := "UPDATE sessions SET revoked_at = now() WHERE id = $1 AND revoked_at IS NULL"
+	if err := databaseGateway.Exec(ctx, sql, sessionId); err != nil {
+		return fmt.Errorf("unable to revoke session: %v", err)
+	}
+
+	if err := cacheGateway.Set(ctx, revokedSessionKey(sessionId), "1", accessTokenTTL); err != nil {
+		return fmt.Errorf("unable to revoke session: %v", err)
+	}
+
+	return nil
+}
+
+// revokeUserSessions ends every session of the user, so their refresh tokens
+// stop working, and denylists each session and every access token issued to
+// the user up to now.
+func revokeUserSessions(
+	ctx context.Context,
+	databaseGateway interfaces.IDatabaseGateway,
+	cacheGateway interfaces.ICacheGateway,
+	accessTokenTTL time.Duration,
+	userId string,
+) error {
+	sql := "UPDATE sessions SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL RETURNING id"
+	resultSet, err := databaseGateway.Query(ctx, sql, userId)
+	if err != nil {
+		return fmt.Errorf("unable to revoke sessions: %v", err)
+	}
+
+	var sessionIds []string
+	for resultSet.Next() {
+		var sessionId string
+		if err := resultSet.Scan(&sessionId); err != nil {
+			resultSet.Close()
+			return fmt.Errorf("unable to revoke sessions: %v", err)
+		}
+		sessionIds = append(sessionIds, sessionId)
+	}
+	resultSet.Close()
+
+	for _, sessionId := range sessionIds {
+		if err := cacheGateway.Set(ctx, revokedSessionKey(sessionId), "1", accessTokenTTL); err != nil {
+			return fmt.Errorf("unable to revoke sessions: %v", err)
+		}
+	}
+
+	if err := cacheGateway.Set(ctx, revokedBeforeKey(userId), revokedBeforeValue(time.Now()), accessTokenTTL); err != nil {
+		return fmt.Errorf("unable to revoke sessions: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package usecases
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/JMCDynamics/maestro-server/internal/utils"
+)
+
+// TracingUseCase runs actor inside a span named after its type, e.g.
+// "FindNodeUseCase", so gateway and node agent spans nest under it.
+type TracingUseCase[T any, R any] struct {
+	name  string
+	actor interfaces.IUseCase[T, R]
+}
+
+func NewTracingUseCase[T any, R any](actor interfaces.IUseCase[T, R]) *TracingUseCase[T, R] {
+	name := reflect.TypeOf(actor)
+	if name.Kind() == reflect.Pointer {
+		name = name.Elem()
+	}
+
+	return &TracingUseCase[T, R]{name: name.Name(), actor: actor}
+}
+
+func (u *TracingUseCase[T, R]) Execute(ctx context.Context, props T) (R, error) {
+	ctx, span := utils.Tracer().Start(ctx, u.name)
+	result, err := u.actor.Execute(ctx, props)
+	utils.EndSpan(span, err)
+
+	return result, err
+}
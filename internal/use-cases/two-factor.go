@@ -0,0 +1,221 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/JMCDynamics/maestro-server/internal/utils"
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	twoFactorChallengeTTL  = 5 * time.Minute
+	twoFactorMaxAttempts   = 5
+	recoveryCodeCount      = 10
+	totpReplayTTL          = 2 * time.Minute
+	authPolicySettingKey   = "auth_policy"
+	totpCodeLength         = 6
+	recoveryCodeHalfLength = 5
+)
+
+var (
+	ErrInvalidTwoFactorCode error = errs.Unauthorized("invalid two-factor code")
+	ErrTotpNotEnrolled      error = errs.BadRequest("two-factor authentication has not been set up")
+	ErrTotpAlreadyEnabled   error = errs.Conflict("two-factor authentication is already enabled")
+	ErrTwoFactorRequired    error = errs.Forbidden("two-factor authentication is required by policy")
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func twoFactorChallengeKey(token string) string {
+	return "auth:challenge:" + hashToken(token)
+}
+
+func twoFactorChallengeAttemptsKey(token string) string {
+	return "auth:challenge-attempts:" + hashToken(token)
+}
+
+type totpState struct {
+	username string
+	secret   string
+	enabled  bool
+	disabled bool
+}
+
+func findTotpState(ctx context.Context, databaseGateway interfaces.IDatabaseGateway, userId string) (totpState, error) {
+	sql := "SELECT username, COALESCE(totp_secret, ''), totp_enabled, disabled FROM users WHERE id = $1"
+	resultSet, err := databaseGateway.Query(ctx, sql, userId)
+	if err != nil {
+		return totpState{}, errors.New("unable to find user")
+	}
+	defer resultSet.Close()
+
+	if !resultSet.Next() {
+		return totpState{}, ErrUserNotFound
+	}
+
+	var state totpState
+	if err := resultSet.Scan(&state.username, &state.secret, &state.enabled, &state.disabled); err != nil {
+		return totpState{}, fmt.Errorf("failed to scan user: %w", err)
+	}
+
+	return state, nil
+}
+
+// startTotpEnrollment stores a fresh, not yet confirmed secret for the user,
+// replacing any earlier unconfirmed one.
+func startTotpEnrollment(ctx context.Context, databaseGateway interfaces.IDatabaseGateway, issuer, userId, username string) (dtos.TotpEnrollment, error) {
+	secret, err := utils.GenerateTotpSecret()
+	if err != nil {
+		return dtos.TotpEnrollment{}, fmt.Errorf("unable to generate totp secret: %v", err)
+	}
+
+	sql := "UPDATE users SET totp_secret = $1, totp_enabled = false WHERE id = $2"
+	if err := databaseGateway.Exec(ctx, sql, secret, userId); err != nil {
+		return dtos.TotpEnrollment{}, fmt.Errorf("unable to store totp secret: %v", err)
+	}
+
+	uri := utils.TotpURI(issuer, username, secret)
+	qrCode, err := utils.TotpQRCode(uri)
+	if err != nil {
+		return dtos.TotpEnrollment{}, fmt.Errorf("unable to render qr code: %v", err)
+	}
+
+	return dtos.TotpEnrollment{Secret: secret, URI: uri, QRCode: qrCode}, nil
+}
+
+// verifyTotp accepts each code once: the matched time step is remembered in
+// Redis so a code seen over someone's shoulder cannot be replayed.
+func verifyTotp(ctx context.Context, cacheGateway interfaces.ICacheGateway, userId, secret, code string) (bool, error) {
+	step, ok := utils.ValidateTotp(secret, code, time.Now())
+	if !ok {
+		return false, nil
+	}
+
+	key := fmt.Sprintf("auth:totp-used:%s:%d", userId, step)
+	fresh, err := cacheGateway.SetIfNotExists(ctx, key, "1", totpReplayTTL)
+	if err != nil {
+		return false, fmt.Errorf("unable to record totp code: %v", err)
+	}
+
+	return fresh, nil
+}
+
+// verifySecondFactor checks a TOTP code, or when allowRecovery is set also a
+// recovery code, which is spent on success.
+func verifySecondFactor(
+	ctx context.Context,
+	databaseGateway interfaces.IDatabaseGateway,
+	cacheGateway interfaces.ICacheGateway,
+	userId, secret, code string,
+	allowRecovery bool,
+) (bool, error) {
+	code = normalizeTwoFactorCode(code)
+
+	if len(code) == totpCodeLength {
+		return verifyTotp(ctx, cacheGateway, userId, secret, code)
+	}
+
+	if !allowRecovery || len(code) != recoveryCodeHalfLength*2 {
+		return false, nil
+	}
+
+	sql := "UPDATE recovery_codes SET used_at = now() WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL RETURNING id"
+	resultSet, err := databaseGateway.Query(ctx, sql, userId, hashToken(code))
+	if err != nil {
+		return false, fmt.Errorf("unable to use recovery code: %v", err)
+	}
+	defer resultSet.Close()
+
+	return resultSet.Next(), nil
+}
+
+func normalizeTwoFactorCode(code string) string {
+	code = strings.ReplaceAll(code, "-", "")
+	code = strings.ReplaceAll(code, " ", "")
+	return strings.ToLower(code)
+}
+
+// replaceRecoveryCodes discards the user's recovery codes and returns a new
+// set. Only hashes are stored, so the codes are shown exactly once.
+func replaceRecoveryCodes(ctx context.Context, databaseGateway interfaces.IDatabaseGateway, userId string) ([]string, error) {
+	// once the old codes are deleted the new set must be written in full
+	ctx = context.WithoutCancel(ctx)
+
+	if err := databaseGateway.Exec(ctx, "DELETE FROM recovery_codes WHERE user_id = $1", userId); err != nil {
+		return nil, fmt.Errorf("unable to delete recovery codes: %v", err)
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		raw := make([]byte, 8)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, err
+		}
+		code := strings.ToLower(recoveryCodeEncoding.EncodeToString(raw))[:recoveryCodeHalfLength*2]
+
+		sql := "INSERT INTO recovery_codes (id, user_id, code_hash) VALUES($1,$2,$3)"
+		if err := databaseGateway.Exec(ctx, sql, ulid.Make().String(), userId, hashToken(code)); err != nil {
+			return nil, fmt.Errorf("unable to store recovery code: %v", err)
+		}
+
+		codes = append(codes, code[:recoveryCodeHalfLength]+"-"+code[recoveryCodeHalfLength:])
+	}
+
+	return codes, nil
+}
+
+func enableTotp(ctx context.Context, databaseGateway interfaces.IDatabaseGateway, userId string) ([]string, error) {
+	sql := "UPDATE users SET totp_enabled = true, updated_at = now() WHERE id = $1"
+	if err := databaseGateway.Exec(ctx, sql, userId); err != nil {
+		return nil, fmt.Errorf("unable to enable totp: %v", err)
+	}
+
+	return replaceRecoveryCodes(ctx, databaseGateway, userId)
+}
+
+func clearTotp(ctx context.Context, databaseGateway interfaces.IDatabaseGateway, userId string) error {
+	sql := "UPDATE users SET totp_secret = NULL, totp_enabled = false, updated_at = now() WHERE id = $1"
+	if err := databaseGateway.Exec(ctx, sql, userId); err != nil {
+		return fmt.Errorf("unable to disable totp: %v", err)
+	}
+
+	if err := databaseGateway.Exec(ctx, "DELETE FROM recovery_codes WHERE user_id = $1", userId); err != nil {
+		return fmt.Errorf("unable to delete recovery codes: %v", err)
+	}
+
+	return nil
+}
+
+func findAuthPolicy(ctx context.Context, databaseGateway interfaces.IDatabaseGateway) (dtos.AuthPolicy, error) {
+	sql := "SELECT value FROM settings WHERE key = $1"
+	resultSet, err := databaseGateway.Query(ctx, sql, authPolicySettingKey)
+	if err != nil {
+		return dtos.AuthPolicy{}, errors.New("unable to find auth policy")
+	}
+	defer resultSet.Close()
+
+	var policy dtos.AuthPolicy
+	if !resultSet.Next() {
+		return policy, nil
+	}
+
+	var value []byte
+	if err := resultSet.Scan(&value); err != nil {
+		return dtos.AuthPolicy{}, fmt.Errorf("failed to scan auth policy: %w", err)
+	}
+	if err := json.Unmarshal(value, &policy); err != nil {
+		return dtos.AuthPolicy{}, fmt.Errorf("invalid auth policy: %w", err)
+	}
+
+	return policy, nil
+}
@@ -0,0 +1,37 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+type UpdateAuthPolicyUseCase struct {
+	databaseGateway interfaces.IDatabaseGateway
+}
+
+func NewUpdateAuthPolicyUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+) interfaces.IUseCase[dtos.AuthPolicy, dtos.AuthPolicy] {
+	return &UpdateAuthPolicyUseCase{
+		databaseGateway: databaseGateway,
+	}
+}
+
+func (u *UpdateAuthPolicyUseCase) Execute(ctx context.Context, policy dtos.AuthPolicy) (dtos.AuthPolicy, error) {
+	value, err := json.Marshal(policy)
+	if err != nil {
+		return dtos.AuthPolicy{}, err
+	}
+
+	sql := `INSERT INTO settings (key, value, updated_at) VALUES($1,$2,now())
+		ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, updated_at = now()`
+	if err := u.databaseGateway.Exec(ctx, sql, authPolicySettingKey, value); err != nil {
+		return dtos.AuthPolicy{}, fmt.Errorf("unable to update auth policy: %v", err)
+	}
+
+	return policy, nil
+}
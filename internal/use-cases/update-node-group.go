@@ -0,0 +1,54 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+type UpdateNodeGroupUseCase struct {
+	databaseGateway      interfaces.IDatabaseGateway
+	findNodeGroupUseCase interfaces.IUseCase[string, dtos.NodeGroup]
+}
+
+func NewUpdateNodeGroupUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+	findNodeGroupUseCase interfaces.IUseCase[string, dtos.NodeGroup],
+) interfaces.IUseCase[dtos.NodeGroupDTO, dtos.NodeGroup] {
+	return &UpdateNodeGroupUseCase{
+		databaseGateway:      databaseGateway,
+		findNodeGroupUseCase: findNodeGroupUseCase,
+	}
+}
+
+func (u *UpdateNodeGroupUseCase) Execute(ctx context.Context, data dtos.NodeGroupDTO) (dtos.NodeGroup, error) {
+	current, err := u.findNodeGroupUseCase.Execute(ctx, data.Id)
+	if err != nil {
+		return dtos.NodeGroup{}, err
+	}
+
+	group, err := newNodeGroup(ctx, u.databaseGateway, data)
+	if err != nil {
+		return dtos.NodeGroup{}, err
+	}
+	updatedAt := time.Now()
+	group.CreatedAt = current.CreatedAt
+	group.UpdatedAt = &updatedAt
+
+	err = u.databaseGateway.Transaction(ctx, func(ctx context.Context) error {
+		sql := "UPDATE node_groups SET name = $1, description = $2, updated_at = $3 WHERE id = $4"
+		if err := u.databaseGateway.Exec(ctx, sql, group.Name, group.Description, updatedAt, group.Id); err != nil {
+			return fmt.Errorf("unable to update node group: %v", err)
+		}
+
+		return setNodeGroupMembers(ctx, u.databaseGateway, group)
+	})
+	if err != nil {
+		return dtos.NodeGroup{}, err
+	}
+
+	return group, nil
+}
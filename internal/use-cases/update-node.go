@@ -23,10 +23,14 @@ func NewUpdateNodeUseCase(
 	}
 }
 
-func (u *UpdateNodeUseCase) Execute(data dtos.UpdateNodeDTO) (dtos.Node, error) {
+func (u *UpdateNodeUseCase) Execute(ctx context.Context, data dtos.UpdateNodeDTO) (dtos.Node, error) {
+	if err := authorize(data.Scope, dtos.PERM_NODES_WRITE); err != nil {
+		return dtos.Node{}, err
+	}
+
 	var node dtos.Node
 	sqlFind := "SELECT id, name, operating_system FROM nodes WHERE id = $1"
-	resultSet, err := u.databaseGateway.Query(context.Background(), sqlFind, data.Id)
+	resultSet, err := u.databaseGateway.Query(ctx, sqlFind, data.Id)
 	if err != nil {
 		return dtos.Node{}, fmt.Errorf("unable to find a node: %v", err)
 	}
@@ -39,13 +43,18 @@ func (u *UpdateNodeUseCase) Execute(data dtos.UpdateNodeDTO) (dtos.Node, error)
 		return dtos.Node{}, fmt.Errorf("unable to find a node: %v", err)
 	}
 
-	sql := "UPDATE nodes SET name = $1, operating_system = $2 WHERE id = $3"
-	if err := u.databaseGateway.Exec(context.Background(), sql, data.Name, data.OperatingSystem, data.Id); err != nil {
+	labels := data.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	sql := "UPDATE nodes SET name = $1, operating_system = $2, labels = $3 WHERE id = $4"
+	if err := u.databaseGateway.Exec(ctx, sql, data.Name, data.OperatingSystem, labels, data.Id); err != nil {
 		return dtos.Node{}, fmt.Errorf("unable to create a node: %v", err)
 	}
 
 	var status dtos.TypeNodeStatus = dtos.DOWN
-	value, _ := u.cacheGateway.Get(context.Background(), node.Id)
+	value, _ := u.cacheGateway.Get(ctx, node.Id)
 	if value != "" {
 		status = dtos.TypeNodeStatus(value)
 	}
@@ -56,5 +65,6 @@ func (u *UpdateNodeUseCase) Execute(data dtos.UpdateNodeDTO) (dtos.Node, error)
 		OperatingSystem: data.OperatingSystem,
 		VpnAddress:      node.VpnAddress,
 		Status:          status,
+		Labels:          labels,
 	}, nil
 }
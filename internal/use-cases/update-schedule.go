@@ -0,0 +1,75 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+type UpdateScheduleUseCase struct {
+	databaseGateway     interfaces.IDatabaseGateway
+	findScheduleUseCase interfaces.IUseCase[dtos.ScheduleAccessDTO, dtos.Schedule]
+}
+
+func NewUpdateScheduleUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+	findScheduleUseCase interfaces.IUseCase[dtos.ScheduleAccessDTO, dtos.Schedule],
+) interfaces.IUseCase[dtos.ScheduleDTO, dtos.Schedule] {
+	return &UpdateScheduleUseCase{
+		databaseGateway:     databaseGateway,
+		findScheduleUseCase: findScheduleUseCase,
+	}
+}
+
+func (u *UpdateScheduleUseCase) Execute(ctx context.Context, data dtos.ScheduleDTO) (dtos.Schedule, error) {
+	if err := authorizeSchedule(data); err != nil {
+		return dtos.Schedule{}, err
+	}
+
+	current, err := u.findScheduleUseCase.Execute(ctx, dtos.ScheduleAccessDTO{
+		Scope:      data.Scope,
+		ScheduleId: data.Id,
+	})
+	if err != nil {
+		return dtos.Schedule{}, err
+	}
+
+	data.CreatedBy = current.CreatedBy
+	// responses mask env values, so a schedule sent back as it was read
+	// keeps the values it has
+	for name, value := range data.Action.Env {
+		if stored, ok := current.Action.Env[name]; ok && value == dtos.MASKED_ENV_VALUE {
+			data.Action.Env[name] = stored
+		}
+	}
+	schedule, err := newSchedule(data)
+	if err != nil {
+		return dtos.Schedule{}, err
+	}
+	schedule.LastRunAt = current.LastRunAt
+	schedule.CreatedAt = current.CreatedAt
+
+	sql := `UPDATE schedules
+		SET name = $1, cron_expression = $2, selector = $3, action_type = $4, action = $5, max_retries = $6, retry_delay_ms = $7, enabled = $8, next_run_at = $9, updated_at = now()
+		WHERE id = $10`
+	if err := u.databaseGateway.Exec(
+		ctx,
+		sql,
+		schedule.Name,
+		schedule.CronExpression,
+		schedule.Selector,
+		schedule.ActionType,
+		schedule.Action,
+		schedule.MaxRetries,
+		schedule.RetryDelayMs,
+		schedule.Enabled,
+		schedule.NextRunAt,
+		schedule.Id,
+	); err != nil {
+		return dtos.Schedule{}, fmt.Errorf("unable to update schedule: %v", err)
+	}
+
+	return schedule, nil
+}
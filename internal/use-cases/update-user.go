@@ -0,0 +1,106 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+)
+
+type UpdateUserUseCase struct {
+	databaseGateway           interfaces.IDatabaseGateway
+	cacheGateway              interfaces.ICacheGateway
+	findUserUseCase           interfaces.IUseCase[string, dtos.User]
+	revokeUserSessionsUseCase interfaces.IUseCase[string, any]
+	accessTokenTTL            time.Duration
+}
+
+var (
+	ErrCannotDisableSelf   error = errs.BadRequest("you cannot disable your own account")
+	ErrCannotChangeOwnRole error = errs.BadRequest("you cannot change your own role")
+)
+
+func NewUpdateUserUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+	cacheGateway interfaces.ICacheGateway,
+	findUserUseCase interfaces.IUseCase[string, dtos.User],
+	revokeUserSessionsUseCase interfaces.IUseCase[string, any],
+	accessTokenTTL time.Duration,
+) interfaces.IUseCase[dtos.UpdateUserDTO, dtos.User] {
+	return &UpdateUserUseCase{
+		databaseGateway:           databaseGateway,
+		cacheGateway:              cacheGateway,
+		findUserUseCase:           findUserUseCase,
+		revokeUserSessionsUseCase: revokeUserSessionsUseCase,
+		accessTokenTTL:            accessTokenTTL,
+	}
+}
+
+func (u *UpdateUserUseCase) Execute(ctx context.Context, data dtos.UpdateUserDTO) (dtos.User, error) {
+	user, err := u.findUserUseCase.Execute(ctx, data.Id)
+	if err != nil {
+		return dtos.User{}, err
+	}
+
+	role := data.Role
+	if role == "" {
+		role = user.Role
+	}
+
+	if data.Id == data.ActorId {
+		if data.Disabled {
+			return dtos.User{}, ErrCannotDisableSelf
+		}
+		if role != user.Role {
+			return dtos.User{}, ErrCannotChangeOwnRole
+		}
+	}
+
+	taken, err := usernameTaken(ctx, u.databaseGateway, data.Username, data.Id)
+	if err != nil {
+		return dtos.User{}, err
+	}
+	if taken {
+		return dtos.User{}, ErrUsernameTaken
+	}
+
+	updatedAt := time.Now()
+	sql := "UPDATE users SET username = $1, role = $2, disabled = $3, updated_at = $4 WHERE id = $5"
+	if err := u.databaseGateway.Exec(ctx, sql, data.Username, role, data.Disabled, updatedAt, data.Id); err != nil {
+		return dtos.User{}, fmt.Errorf("unable to update user: %v", err)
+	}
+
+	// A disabled user is signed out everywhere and the tokens they still
+	// hold are rejected until they expire.
+	switch {
+	case data.Disabled && !user.Disabled:
+		if err := u.cacheGateway.Set(ctx, userDisabledKey(data.Id), "1", u.accessTokenTTL); err != nil {
+			return dtos.User{}, fmt.Errorf("unable to revoke user tokens: %v", err)
+		}
+		if _, err := u.revokeUserSessionsUseCase.Execute(ctx, data.Id); err != nil {
+			return dtos.User{}, err
+		}
+	case !data.Disabled && user.Disabled:
+		if err := u.cacheGateway.Delete(ctx, userDisabledKey(data.Id)); err != nil {
+			return dtos.User{}, fmt.Errorf("unable to enable user: %v", err)
+		}
+	}
+
+	// Access tokens carry the role they were issued with, so the ones issued
+	// before a role change are rejected and the user has to refresh them.
+	if role != user.Role {
+		if err := u.cacheGateway.Set(ctx, revokedBeforeKey(data.Id), revokedBeforeValue(time.Now()), u.accessTokenTTL); err != nil {
+			return dtos.User{}, fmt.Errorf("unable to revoke user tokens: %v", err)
+		}
+	}
+
+	user.Username = data.Username
+	user.Role = role
+	user.Disabled = data.Disabled
+	user.UpdatedAt = &updatedAt
+
+	return user, nil
+}
@@ -0,0 +1,217 @@
+package usecases
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/JMCDynamics/maestro-server/internal/utils"
+)
+
+// uploadProgressTTL bounds how long a chunked upload may pause between chunks.
+const uploadProgressTTL = 24 * time.Hour
+
+var (
+	ErrChecksumMismatch  error = errs.Unprocessable("sha-256 checksum does not match the uploaded file")
+	ErrChunkIncomplete   error = errs.BadRequest("chunk is shorter than its Content-Range")
+	ErrUploadSizeChanged error = errs.Conflict("chunk announces a different file size than the upload in progress")
+)
+
+type UploadNodeFileUseCase struct {
+	cacheGateway    interfaces.ICacheGateway
+	findNodeUseCase interfaces.IUseCase[string, dtos.Node]
+	client          *http.Client
+}
+
+func NewUploadNodeFileUseCase(
+	cacheGateway interfaces.ICacheGateway,
+	findNodeUseCase interfaces.IUseCase[string, dtos.Node],
+) interfaces.IUseCase[dtos.UploadFileDTO, dtos.FileTransfer] {
+	return &UploadNodeFileUseCase{
+		cacheGateway:    cacheGateway,
+		findNodeUseCase: findNodeUseCase,
+		client:          utils.NewNodeAgentClient(0),
+	}
+}
+
+func uploadProgressKey(nodeId, path string) string {
+	return "upload:progress:" + nodeId + ":" + path
+}
+
+// Execute streams a file, or one chunk of it, to the node agent's PUT /files
+// endpoint, which writes the body at the given offset. Chunks must arrive in
+// order: the offset the next one has to start at is kept in Redis, so the
+// upload is complete only once every byte has been written. The digest is
+// then checked against the one the client announced, and a file that does
+// not match is removed from the node.
+func (u *UploadNodeFileUseCase) Execute(ctx context.Context, data dtos.UploadFileDTO) (dtos.FileTransfer, error) {
+	if err := authorize(data.Scope, dtos.PERM_FILES_WRITE); err != nil {
+		return dtos.FileTransfer{}, err
+	}
+
+	node, err := u.findNodeUseCase.Execute(ctx, data.NodeId)
+	if err != nil {
+		return dtos.FileTransfer{}, err
+	}
+
+	chunked := data.Total > 0
+	progressKey := uploadProgressKey(node.Id, data.Path)
+	if chunked {
+		if err := u.checkChunkOrder(ctx, progressKey, data); err != nil {
+			return dtos.FileTransfer{}, err
+		}
+	}
+
+	var digest hash.Hash
+	body := &countingReader{reader: data.Body}
+	if !chunked {
+		digest = sha256.New()
+		body.reader = io.TeeReader(data.Body, digest)
+	}
+
+	query := url.Values{
+		"path":   {data.Path},
+		"offset": {strconv.FormatInt(data.Offset, 10)},
+	}
+	req, err := utils.NewNodeAgentRequest(ctx, http.MethodPut, nodeAgentFileURL(node, "/files", query), body)
+	if err != nil {
+		return dtos.FileTransfer{}, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := u.client.Do(req)
+	if body.err != nil {
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return dtos.FileTransfer{}, body.err
+	}
+	if err != nil {
+		return dtos.FileTransfer{}, ErrNodeAgentUnavailable
+	}
+	defer resp.Body.Close()
+
+	if err := checkNodeAgentResponse(resp); err != nil {
+		return dtos.FileTransfer{}, err
+	}
+
+	transfer := dtos.FileTransfer{
+		Path:     data.Path,
+		Received: data.Offset + body.read,
+		Total:    data.Total,
+	}
+	if !chunked {
+		transfer.Total = transfer.Received
+	}
+
+	if chunked {
+		// a short chunk is sent again from the same offset
+		if body.read != data.Length {
+			return dtos.FileTransfer{}, ErrChunkIncomplete
+		}
+
+		if transfer.Received < transfer.Total {
+			value := strconv.FormatInt(transfer.Received, 10) + "/" + strconv.FormatInt(transfer.Total, 10)
+			if err := u.cacheGateway.Set(ctx, progressKey, value, uploadProgressTTL); err != nil {
+				return dtos.FileTransfer{}, fmt.Errorf("unable to store upload progress: %v", err)
+			}
+			return transfer, nil
+		}
+
+		if err := u.cacheGateway.Delete(ctx, progressKey); err != nil {
+			return dtos.FileTransfer{}, fmt.Errorf("unable to store upload progress: %v", err)
+		}
+	}
+
+	transfer.Complete = true
+
+	if digest != nil {
+		transfer.Sha256 = hex.EncodeToString(digest.Sum(nil))
+	} else {
+		var checksum struct {
+			Sha256 string `json:"sha256"`
+		}
+		target := nodeAgentFileURL(node, "/files/checksum", url.Values{"path": {data.Path}})
+		if err := getNodeAgentJSON(ctx, u.client, target, &checksum); err != nil {
+			return transfer, err
+		}
+		transfer.Sha256 = checksum.Sha256
+	}
+
+	if data.Sha256 != "" && !strings.EqualFold(data.Sha256, transfer.Sha256) {
+		u.remove(context.WithoutCancel(ctx), node, data.Path)
+		return transfer, ErrChecksumMismatch
+	}
+
+	return transfer, nil
+}
+
+// checkChunkOrder rejects a chunk that does not start where the previous one
+// ended, or that belongs to an upload of a different size. An upload starts
+// with the chunk at offset zero, which also restarts an unfinished one.
+func (u *UploadNodeFileUseCase) checkChunkOrder(ctx context.Context, progressKey string, data dtos.UploadFileDTO) error {
+	if data.Offset == 0 {
+		return nil
+	}
+
+	value, err := u.cacheGateway.Get(ctx, progressKey)
+	if err != nil && err != interfaces.ErrKeyNotFound {
+		return fmt.Errorf("unable to find upload progress: %v", err)
+	}
+
+	var next, total int64
+	if err == nil {
+		if _, err := fmt.Sscanf(value, "%d/%d", &next, &total); err != nil {
+			return fmt.Errorf("invalid upload progress: %v", err)
+		}
+		if total != data.Total {
+			return ErrUploadSizeChanged
+		}
+	}
+
+	if data.Offset != next {
+		return errs.Conflict(fmt.Sprintf("chunk must start at byte %d", next))
+	}
+
+	return nil
+}
+
+func (u *UploadNodeFileUseCase) remove(ctx context.Context, node dtos.Node, path string) {
+	req, err := utils.NewNodeAgentRequest(ctx, http.MethodDelete, nodeAgentFileURL(node, "/files", url.Values{"path": {path}}), nil)
+	if err != nil {
+		return
+	}
+
+	if resp, err := u.client.Do(req); err == nil {
+		resp.Body.Close()
+	}
+}
+
+// countingReader keeps track of how much of the client's body was consumed
+// and remembers why reading it failed, so that a client error is not reported
+// as the node agent being unreachable.
+type countingReader struct {
+	reader io.Reader
+	read   int64
+	err    error
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.read += int64(n)
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+	return n, err
+}
@@ -0,0 +1,146 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/JMCDynamics/maestro-server/internal/errs"
+	"github.com/JMCDynamics/maestro-server/internal/interfaces"
+	"github.com/JMCDynamics/maestro-server/internal/utils"
+)
+
+var (
+	ErrInvalidChallenge error = errs.Unauthorized("login challenge is invalid or expired")
+)
+
+type VerifyTwoFactorUseCase struct {
+	databaseGateway      interfaces.IDatabaseGateway
+	cacheGateway         interfaces.ICacheGateway
+	createSessionUseCase interfaces.IUseCase[dtos.CreateSessionDTO, dtos.TokenPair]
+	policy               dtos.LoginThrottlePolicy
+}
+
+func NewVerifyTwoFactorUseCase(
+	databaseGateway interfaces.IDatabaseGateway,
+	cacheGateway interfaces.ICacheGateway,
+	createSessionUseCase interfaces.IUseCase[dtos.CreateSessionDTO, dtos.TokenPair],
+	policy dtos.LoginThrottlePolicy,
+) interfaces.IUseCase[dtos.VerifyTwoFactorDTO, dtos.AuthResult] {
+	return &VerifyTwoFactorUseCase{
+		databaseGateway:      databaseGateway,
+		cacheGateway:         cacheGateway,
+		createSessionUseCase: createSessionUseCase,
+		policy:               policy,
+	}
+}
+
+// Execute completes a login challenge issued by POST /auth. A challenge
+// survives a few wrong codes and is then discarded, forcing the caller to
+// present the password again. Wrong codes are also counted per user across
+// challenges, which locks the second factor out like a password, so holding
+// the password is not enough to guess the code.
+func (u *VerifyTwoFactorUseCase) Execute(ctx context.Context, data dtos.VerifyTwoFactorDTO) (dtos.AuthResult, error) {
+	key := twoFactorChallengeKey(data.ChallengeToken)
+	value, err := u.cacheGateway.Get(ctx, key)
+	if err == interfaces.ErrKeyNotFound {
+		return dtos.AuthResult{}, ErrInvalidChallenge
+	}
+	if err != nil {
+		return dtos.AuthResult{}, fmt.Errorf("unable to read challenge: %v", err)
+	}
+
+	var challenge dtos.TwoFactorChallengeState
+	if err := json.Unmarshal([]byte(value), &challenge); err != nil {
+		return dtos.AuthResult{}, ErrInvalidChallenge
+	}
+
+	remaining := time.Until(challenge.ExpiresAt)
+	if remaining <= 0 {
+		u.cacheGateway.Delete(ctx, key)
+		return dtos.AuthResult{}, ErrInvalidChallenge
+	}
+
+	// Every guess is counted before the code is compared, so concurrent
+	// guesses cannot get past either limit.
+	attempts, err := u.cacheGateway.Increment(ctx, twoFactorChallengeAttemptsKey(data.ChallengeToken), remaining)
+	if err != nil {
+		return dtos.AuthResult{}, fmt.Errorf("unable to count two-factor attempts: %v", err)
+	}
+	if attempts > twoFactorMaxAttempts {
+		u.cacheGateway.Delete(ctx, key)
+		return dtos.AuthResult{}, ErrInvalidChallenge
+	}
+
+	throttle := loginThrottle{subject: "2fa", value: challenge.UserId, maxAttempts: u.policy.MaxAttemptsPerUser}
+	reservation, err := reserveAttempt(ctx, u.cacheGateway, u.policy, throttle)
+	if err != nil {
+		return dtos.AuthResult{}, err
+	}
+	if reservation.lockout > 0 {
+		utils.Logger(ctx).Warn().
+			Str("event", "auth_lockout").
+			Str("throttle", throttle.subject).
+			Str("user-id", throttle.value).
+			Int64("attempts", reservation.attempts).
+			Dur("lockout", reservation.lockout).
+			Msg("two-factor locked out after repeated failures")
+	}
+
+	state, err := findTotpState(ctx, u.databaseGateway, challenge.UserId)
+	if err == ErrUserNotFound {
+		return dtos.AuthResult{}, ErrInvalidChallenge
+	}
+	if err != nil {
+		return dtos.AuthResult{}, err
+	}
+	if state.secret == "" {
+		return dtos.AuthResult{}, ErrInvalidChallenge
+	}
+	if state.disabled {
+		u.cacheGateway.Delete(ctx, key)
+		return dtos.AuthResult{}, ErrUserDisabled
+	}
+
+	// Recovery codes only exist once enrolment is complete.
+	valid, err := verifySecondFactor(ctx, u.databaseGateway, u.cacheGateway, challenge.UserId, state.secret, data.Code, !challenge.EnrollmentRequired)
+	if err != nil {
+		return dtos.AuthResult{}, err
+	}
+
+	if !valid {
+		if attempts >= twoFactorMaxAttempts {
+			u.cacheGateway.Delete(ctx, key)
+		}
+		return dtos.AuthResult{}, ErrInvalidTwoFactorCode
+	}
+
+	u.cacheGateway.Delete(ctx, key)
+	clearAttempts(ctx, u.cacheGateway, throttle)
+
+	var result dtos.AuthResult
+	if challenge.EnrollmentRequired {
+		if result.RecoveryCodes, err = enableTotp(ctx, u.databaseGateway, challenge.UserId); err != nil {
+			return dtos.AuthResult{}, err
+		}
+	}
+
+	if err := recordLogin(ctx, u.databaseGateway, challenge.UserId); err != nil {
+		return dtos.AuthResult{}, err
+	}
+
+	tokens, err := u.createSessionUseCase.Execute(ctx, dtos.CreateSessionDTO{
+		UserId:    challenge.UserId,
+		Role:      challenge.Role,
+		UserAgent: challenge.UserAgent,
+		IpAddress: challenge.IpAddress,
+	})
+	if err != nil {
+		return dtos.AuthResult{}, err
+	}
+	result.TokenPair = &tokens
+
+	return result, nil
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+type contextKey string
+
+const (
+	requestIdKey contextKey = "requestId"
+	userIdKey    contextKey = "userId"
+	loggerKey    contextKey = "logger"
+)
+
+// WithRequestId stores the id of the HTTP request being served in ctx and
+// adds it to the logger returned by Logger.
+func WithRequestId(ctx context.Context, requestId string) context.Context {
+	ctx = context.WithValue(ctx, requestIdKey, requestId)
+	return withLogger(ctx, Logger(ctx).With().Str("request_id", requestId).Logger())
+}
+
+// RequestId returns the request id stored in ctx, or "".
+func RequestId(ctx context.Context) string {
+	requestId, _ := ctx.Value(requestIdKey).(string)
+	return requestId
+}
+
+// WithUserId stores the authenticated caller in ctx and adds it to the logger
+// returned by Logger.
+func WithUserId(ctx context.Context, userId string) context.Context {
+	ctx = context.WithValue(ctx, userIdKey, userId)
+	return withLogger(ctx, Logger(ctx).With().Str("user_id", userId).Logger())
+}
+
+// UserId returns the authenticated caller stored in ctx, or "".
+func UserId(ctx context.Context) string {
+	userId, _ := ctx.Value(userIdKey).(string)
+	return userId
+}
+
+// Logger returns the global logger with the request id and caller stored in
+// ctx, so every line logged while serving a request can be correlated.
+func Logger(ctx context.Context) *zerolog.Logger {
+	if logger, ok := ctx.Value(loggerKey).(*zerolog.Logger); ok {
+		return logger
+	}
+
+	return &log.Logger
+}
+
+func withLogger(ctx context.Context, logger zerolog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, &logger)
+}
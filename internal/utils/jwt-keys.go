@@ -0,0 +1,176 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// HMACKeyId identifies the legacy key derived from MAESTRO_SECRET_KEY.
+const HMACKeyId = "hs256"
+
+// JWTKey is one entry of a JWTKeySet. Keys loaded from a public key can only
+// verify tokens; they let tokens signed before a rotation live out their TTL.
+type JWTKey struct {
+	Id        string
+	method    jwt.SigningMethod
+	signKey   any
+	verifyKey any
+}
+
+func (k JWTKey) CanSign() bool {
+	return k.signKey != nil
+}
+
+// NewHMACKey wraps a shared secret as an HS256 key.
+func NewHMACKey(id, secret string) JWTKey {
+	return JWTKey{
+		Id:        id,
+		method:    jwt.SigningMethodHS256,
+		signKey:   []byte(secret),
+		verifyKey: []byte(secret),
+	}
+}
+
+// LoadJWTKey reads a PEM encoded Ed25519 or RSA key. Private keys sign with
+// EdDSA or RS256; public keys are accepted for verification only.
+func LoadJWTKey(id, path string) (JWTKey, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return JWTKey{}, fmt.Errorf("unable to read jwt key %s: %v", id, err)
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return JWTKey{}, fmt.Errorf("jwt key %s is not PEM encoded", id)
+	}
+
+	var key any
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return JWTKey{}, fmt.Errorf("jwt key %s has unsupported PEM type %q", id, block.Type)
+	}
+	if err != nil {
+		return JWTKey{}, fmt.Errorf("unable to parse jwt key %s: %v", id, err)
+	}
+
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		return JWTKey{Id: id, method: jwt.SigningMethodEdDSA, signKey: k, verifyKey: k.Public()}, nil
+	case ed25519.PublicKey:
+		return JWTKey{Id: id, method: jwt.SigningMethodEdDSA, verifyKey: k}, nil
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < 2048 {
+			return JWTKey{}, fmt.Errorf("jwt key %s: rsa keys must be at least 2048 bits", id)
+		}
+		return JWTKey{Id: id, method: jwt.SigningMethodRS256, signKey: k, verifyKey: &k.PublicKey}, nil
+	case *rsa.PublicKey:
+		return JWTKey{Id: id, method: jwt.SigningMethodRS256, verifyKey: k}, nil
+	default:
+		return JWTKey{}, fmt.Errorf("jwt key %s: only ed25519 and rsa keys are supported", id)
+	}
+}
+
+// JWTKeySet signs access tokens with its active key and verifies tokens
+// signed by any of its keys, picked by the "kid" header.
+type JWTKeySet struct {
+	active JWTKey
+	keys   map[string]JWTKey
+}
+
+func NewJWTKeySet(activeKeyId string, keys ...JWTKey) (*JWTKeySet, error) {
+	keySet := &JWTKeySet{keys: map[string]JWTKey{}}
+	for _, key := range keys {
+		if _, ok := keySet.keys[key.Id]; ok {
+			return nil, fmt.Errorf("duplicate jwt key id %q", key.Id)
+		}
+		keySet.keys[key.Id] = key
+	}
+
+	active, ok := keySet.keys[activeKeyId]
+	if !ok {
+		return nil, fmt.Errorf("active jwt key %q is not configured", activeKeyId)
+	}
+	if !active.CanSign() {
+		return nil, fmt.Errorf("active jwt key %q is a public key and cannot sign", activeKeyId)
+	}
+	keySet.active = active
+
+	return keySet, nil
+}
+
+func (s *JWTKeySet) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(s.active.method, claims)
+	token.Header["kid"] = s.active.Id
+
+	return token.SignedString(s.active.signKey)
+}
+
+func (s *JWTKeySet) verificationKey(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		// tokens issued before key ids were introduced
+		kid = HMACKeyId
+	}
+
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, errors.New("unknown signing key")
+	}
+
+	// the key, not the token, decides the algorithm
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, jwt.ErrSignatureInvalid
+	}
+
+	return key.verifyKey, nil
+}
+
+// JWKS lists the public keys of the set. Shared HMAC secrets are never
+// published.
+func (s *JWTKeySet) JWKS() dtos.JWKS {
+	jwks := dtos.JWKS{Keys: []dtos.JWK{}}
+	for _, key := range s.keys {
+		jwk := dtos.JWK{Kid: key.Id, Use: "sig", Alg: key.method.Alg()}
+
+		switch k := key.verifyKey.(type) {
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(k)
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(k.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes())
+		default:
+			continue
+		}
+
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	slices.SortFunc(jwks.Keys, func(a, b dtos.JWK) int {
+		return strings.Compare(a.Kid, b.Kid)
+	})
+
+	return jwks
+}
@@ -1,16 +1,60 @@
 package utils
 
 import (
+	"errors"
 	"time"
 
+	"github.com/JMCDynamics/maestro-server/internal/dtos"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/oklog/ulid/v2"
 )
 
-func GenerateJWT(userId, jwtSecret string) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+// GenerateJWT issues an access token for a session, signed with the key
+// set's active key. Access tokens are short lived; clients renew them with the
+// session's refresh token.
+func GenerateJWT(userId, role, sessionId string, keySet *JWTKeySet, expiresAt time.Time) (string, error) {
+	return keySet.sign(jwt.MapClaims{
+		"jti":    ulid.Make().String(),
+		"sid":    sessionId,
 		"userId": userId,
-		"exp":    time.Now().Add(time.Hour * 12).Unix(),
+		"role":   role,
+		"iat":    time.Now().Unix(),
+		"exp":    expiresAt.Unix(),
 	})
+}
+
+// ParseJWT verifies the signature and expiry of a token issued by
+// GenerateJWT and returns its claims.
+func ParseJWT(tokenString string, keySet *JWTKeySet) (dtos.TokenClaims, error) {
+	token, err := jwt.Parse(tokenString, keySet.verificationKey)
+	if err != nil || !token.Valid {
+		return dtos.TokenClaims{}, errors.New("invalid or expired token")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return dtos.TokenClaims{}, errors.New("invalid token claims")
+	}
+
+	jti, _ := claims["jti"].(string)
+	sessionId, _ := claims["sid"].(string)
+	userId, _ := claims["userId"].(string)
+	role, _ := claims["role"].(string)
+	issuedAt, err := claims.GetIssuedAt()
+	if err != nil || issuedAt == nil || jti == "" || sessionId == "" || userId == "" {
+		return dtos.TokenClaims{}, errors.New("invalid token claims")
+	}
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil || expiresAt == nil {
+		return dtos.TokenClaims{}, errors.New("invalid token claims")
+	}
 
-	return token.SignedString([]byte(jwtSecret))
+	return dtos.TokenClaims{
+		Jti:       jti,
+		SessionId: sessionId,
+		UserId:    userId,
+		Role:      dtos.Role(role),
+		IssuedAt:  issuedAt.Time,
+		ExpiresAt: expiresAt.Time,
+	}, nil
 }
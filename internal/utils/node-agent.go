@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const (
+	NodeAgentPort = "9842"
+
+	RequestIdHeader = "X-Request-Id"
+)
+
+func NodeAgentURL(vpnAddress, path string) string {
+	return fmt.Sprintf("http://%s:%s%s", vpnAddress, NodeAgentPort, path)
+}
+
+func NodeAgentWebSocketURL(vpnAddress, path string) string {
+	return fmt.Sprintf("ws://%s:%s%s", vpnAddress, NodeAgentPort, path)
+}
+
+// NodeAgentHeader forwards the request id and W3C trace context in ctx to
+// the node agent, so its logs and spans can be matched with the server's.
+func NodeAgentHeader(ctx context.Context) http.Header {
+	header := http.Header{}
+	if requestId := RequestId(ctx); requestId != "" {
+		header.Set(RequestIdHeader, requestId)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+
+	return header
+}
+
+// NewNodeAgentClient returns a client that traces every call to a node agent
+// in its own span. A zero timeout leaves streamed responses open.
+func NewNodeAgentClient(timeout time.Duration) *http.Client {
+	transport := otelhttp.NewTransport(
+		http.DefaultTransport,
+		otelhttp.WithSpanNameFormatter(func(_ string, req *http.Request) string {
+			return "node-agent " + req.Method + " " + req.URL.Path
+		}),
+	)
+
+	return &http.Client{Transport: transport, Timeout: timeout}
+}
+
+// NewNodeAgentRequest is http.NewRequestWithContext with NodeAgentHeader set.
+func NewNodeAgentRequest(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range NodeAgentHeader(ctx) {
+		req.Header[key] = values
+	}
+
+	return req, nil
+}
@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const TracerName = "github.com/JMCDynamics/maestro-server"
+
+// Tracer returns the server's tracer from the global provider, a no-op until
+// tracing is configured.
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
+
+// EndSpan marks span as failed when err is set and ends it.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
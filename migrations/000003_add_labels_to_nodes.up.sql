@@ -0,0 +1 @@
+ALTER TABLE nodes ADD COLUMN labels JSONB NOT NULL DEFAULT '{}'::jsonb;
@@ -0,0 +1,20 @@
+CREATE TYPE job_status AS ENUM ('RUNNING', 'SUCCEEDED', 'FAILED', 'CANCELLED', 'TIMED_OUT');
+
+CREATE TABLE jobs (
+    id VARCHAR(255) PRIMARY KEY,
+    node_id VARCHAR(255) NOT NULL REFERENCES nodes(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NULL,
+    command TEXT NOT NULL,
+    args JSONB NOT NULL DEFAULT '[]'::jsonb,
+    env JSONB NOT NULL DEFAULT '{}'::jsonb,
+    working_dir TEXT NOT NULL DEFAULT '',
+    status job_status NOT NULL DEFAULT 'RUNNING',
+    exit_code INTEGER NULL,
+    stdout TEXT NOT NULL DEFAULT '',
+    stderr TEXT NOT NULL DEFAULT '',
+    duration_ms BIGINT NULL,
+    created_at TIMESTAMP DEFAULT now(),
+    finished_at TIMESTAMP NULL
+);
+
+CREATE INDEX jobs_node_id_idx ON jobs (node_id, created_at DESC);
@@ -0,0 +1 @@
+DROP TABLE shell_sessions;
@@ -0,0 +1,12 @@
+CREATE TABLE shell_sessions (
+    id VARCHAR(255) PRIMARY KEY,
+    node_id VARCHAR(255) NOT NULL REFERENCES nodes(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NULL,
+    shell VARCHAR(50) NOT NULL,
+    recording_path TEXT NOT NULL,
+    close_reason TEXT NULL,
+    started_at TIMESTAMP DEFAULT now(),
+    ended_at TIMESTAMP NULL
+);
+
+CREATE INDEX shell_sessions_node_id_idx ON shell_sessions (node_id, started_at DESC);
@@ -0,0 +1,4 @@
+DROP TABLE schedule_runs;
+DROP TABLE schedules;
+DROP TYPE schedule_run_status;
+DROP TYPE schedule_action_type;
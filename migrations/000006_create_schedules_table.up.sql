@@ -0,0 +1,35 @@
+CREATE TYPE schedule_action_type AS ENUM ('PROXY', 'EXEC');
+CREATE TYPE schedule_run_status AS ENUM ('RUNNING', 'SUCCEEDED', 'FAILED', 'SKIPPED');
+
+CREATE TABLE schedules (
+    id VARCHAR(255) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    cron_expression VARCHAR(100) NOT NULL,
+    selector JSONB NOT NULL DEFAULT '{}'::jsonb,
+    action_type schedule_action_type NOT NULL,
+    action JSONB NOT NULL DEFAULT '{}'::jsonb,
+    max_retries INTEGER NOT NULL DEFAULT 0,
+    retry_delay_ms INTEGER NOT NULL DEFAULT 0,
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    created_by VARCHAR(255) NULL,
+    next_run_at TIMESTAMP NULL,
+    last_run_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT now(),
+    updated_at TIMESTAMP NULL
+);
+
+CREATE INDEX schedules_next_run_at_idx ON schedules (next_run_at) WHERE enabled;
+
+CREATE TABLE schedule_runs (
+    id VARCHAR(255) PRIMARY KEY,
+    schedule_id VARCHAR(255) NOT NULL REFERENCES schedules(id) ON DELETE CASCADE,
+    status schedule_run_status NOT NULL DEFAULT 'RUNNING',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    results JSONB NOT NULL DEFAULT '{}'::jsonb,
+    error TEXT NULL,
+    scheduled_for TIMESTAMP NOT NULL,
+    started_at TIMESTAMP DEFAULT now(),
+    finished_at TIMESTAMP NULL
+);
+
+CREATE INDEX schedule_runs_schedule_id_idx ON schedule_runs (schedule_id, scheduled_for DESC);
@@ -0,0 +1,4 @@
+DROP INDEX users_username_idx;
+
+ALTER TABLE users DROP COLUMN last_login_at;
+ALTER TABLE users DROP COLUMN disabled;
@@ -0,0 +1,4 @@
+ALTER TABLE users ADD COLUMN disabled BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE users ADD COLUMN last_login_at TIMESTAMP NULL;
+
+CREATE UNIQUE INDEX users_username_idx ON users (username);
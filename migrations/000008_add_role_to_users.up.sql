@@ -0,0 +1,6 @@
+CREATE TYPE user_role AS ENUM ('ADMIN', 'OPERATOR', 'VIEWER');
+
+ALTER TABLE users ADD COLUMN role user_role NOT NULL DEFAULT 'VIEWER';
+
+-- accounts created before roles existed had full access, keep it that way
+UPDATE users SET role = 'ADMIN';
@@ -0,0 +1,3 @@
+DROP TABLE user_node_groups;
+DROP TABLE node_group_members;
+DROP TABLE node_groups;
@@ -0,0 +1,23 @@
+CREATE TABLE node_groups (
+    id VARCHAR(255) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT now(),
+    updated_at TIMESTAMP NULL
+);
+
+CREATE UNIQUE INDEX node_groups_name_idx ON node_groups (name);
+
+CREATE TABLE node_group_members (
+    group_id VARCHAR(255) NOT NULL REFERENCES node_groups(id) ON DELETE CASCADE,
+    node_id VARCHAR(255) NOT NULL REFERENCES nodes(id) ON DELETE CASCADE,
+    PRIMARY KEY (group_id, node_id)
+);
+
+CREATE INDEX node_group_members_node_id_idx ON node_group_members (node_id);
+
+CREATE TABLE user_node_groups (
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    group_id VARCHAR(255) NOT NULL REFERENCES node_groups(id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, group_id)
+);
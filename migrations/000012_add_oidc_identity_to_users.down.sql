@@ -0,0 +1,4 @@
+DROP INDEX users_oidc_identity_idx;
+
+ALTER TABLE users DROP COLUMN oidc_subject;
+ALTER TABLE users DROP COLUMN oidc_issuer;
@@ -0,0 +1,4 @@
+ALTER TABLE users ADD COLUMN oidc_issuer VARCHAR(255) NULL;
+ALTER TABLE users ADD COLUMN oidc_subject VARCHAR(255) NULL;
+
+CREATE UNIQUE INDEX users_oidc_identity_idx ON users (oidc_issuer, oidc_subject) WHERE oidc_subject IS NOT NULL;
@@ -0,0 +1,5 @@
+DROP TABLE settings;
+DROP TABLE recovery_codes;
+
+ALTER TABLE users DROP COLUMN totp_enabled;
+ALTER TABLE users DROP COLUMN totp_secret;
@@ -0,0 +1,29 @@
+CREATE TABLE audit_log (
+    id VARCHAR(255) PRIMARY KEY,
+    actor_id VARCHAR(255) NOT NULL DEFAULT '',
+    api_key_id VARCHAR(255) NOT NULL DEFAULT '',
+    action VARCHAR(100) NOT NULL,
+    node_id VARCHAR(255) NOT NULL DEFAULT '',
+    target TEXT NOT NULL DEFAULT '',
+    ip_address VARCHAR(64) NOT NULL DEFAULT '',
+    request_id VARCHAR(255) NOT NULL DEFAULT '',
+    outcome VARCHAR(20) NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX audit_log_actor_id_idx ON audit_log (actor_id, id);
+CREATE INDEX audit_log_node_id_idx ON audit_log (node_id, id);
+CREATE INDEX audit_log_created_at_idx ON audit_log (created_at);
+
+-- Entries outlive the users and nodes they mention, so there are no foreign
+-- keys, and nothing may rewrite or remove them.
+CREATE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE OR TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();